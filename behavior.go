@@ -0,0 +1,80 @@
+package fieldmask
+
+import (
+	"reflect"
+	"sync"
+)
+
+// FieldBehavior declares how masks treat a field, after the field behaviors of AIP-203. Behaviors are declared with
+// the required and output_only options of the fieldmask struct tag, or with RegisterFieldBehavior for types that
+// cannot be tagged, and can be combined.
+type FieldBehavior uint8
+
+const (
+	// Required marks a field that Apply and Project keep whatever the mask says, such as the id, name or etag
+	// identifying a resource. It is what the required tag option declares.
+	Required FieldBehavior = 1 << iota
+	// OutputOnly marks a field set by the server, such as a creation time. Merge never writes it, and
+	// ValidateUpdate rejects update masks naming it.
+	OutputOnly
+)
+
+type behaviorKey struct {
+	typ   reflect.Type
+	field string
+}
+
+var (
+	behaviorMu sync.Mutex
+	behaviors  sync.Map
+)
+
+// RegisterFieldBehavior declares behaviors for the field of the struct type T with the given Go name, in addition to
+// those declared by its struct tags. The field must be declared by T itself rather than promoted from an embedded
+// struct. See Registration in the package documentation for when to call it.
+func RegisterFieldBehavior[T any](field string, behavior FieldBehavior) error {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ErrNoStruct
+	}
+
+	if sf, ok := t.FieldByName(field); !ok || len(sf.Index) != 1 {
+		return &errFieldProcessing{fieldName: field, err: ErrUnknownField}
+	}
+
+	behaviorMu.Lock()
+	defer behaviorMu.Unlock()
+
+	key := behaviorKey{typ: t, field: field}
+	behaviors.Store(key, registeredBehavior(t, field)|behavior)
+	descriptorCache.Clear()
+	return nil
+}
+
+// registeredBehavior returns the behaviors registered for the field of the struct type t with the given Go name.
+func registeredBehavior(t reflect.Type, field string) FieldBehavior {
+	if behavior, ok := behaviors.Load(behaviorKey{typ: t, field: field}); ok {
+		return behavior.(FieldBehavior)
+	}
+	return 0
+}
+
+// ValidateUpdate checks that the FieldMask is a valid update mask for the struct type t, or a pointer to it. On top
+// of the checks of Validate, it rejects paths naming an output-only field or a field below one, with a field
+// processing error wrapping ErrOutputOnly. Wildcard segments are accepted, since Merge skips output-only fields.
+//
+// Paths naming immutable fields are accepted: unlike output-only fields, which clients never set, immutable fields are
+// set on creation and commonly sent back unchanged along with the rest of the resource, as AIP-203 allows. Whether
+// their value changes cannot be told from the mask alone, and Merge keeps the stored value either way.
+func (f *FieldMask) ValidateUpdate(t reflect.Type) error {
+	return defaultResolver.ValidateUpdate(f, t)
+}
+
+// ValidateUpdateFor checks that the FieldMask is a valid update mask for the struct type T.
+// See FieldMask.ValidateUpdate.
+func ValidateUpdateFor[T any](f *FieldMask) error {
+	return f.ValidateUpdate(reflect.TypeFor[T]())
+}
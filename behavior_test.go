@@ -0,0 +1,252 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldBehavior_Apply(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by"`
+	}
+
+	type Book struct {
+		Name       string `json:"name" fieldmask:",required"`
+		Etag       string `json:"etag" fieldmask:",required"`
+		Title      string `json:"title"`
+		CreateTime int64  `json:"create_time" fieldmask:",output_only"`
+		Audit      *Audit `json:"audit" fieldmask:",output_only"`
+	}
+
+	book := &Book{Name: "books/1", Etag: "abc", Title: "Go", CreateTime: 1, Audit: &Audit{CreatedBy: "me"}}
+	if err := fieldmask.New("title").Apply(book); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+
+	want := &Book{Name: "books/1", Etag: "abc", Title: "Go"}
+	if !reflect.DeepEqual(book, want) {
+		t.Errorf("Apply() = %+v, want %+v", book, want)
+	}
+}
+
+func TestFieldBehavior_Merge(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by"`
+	}
+
+	type Book struct {
+		Name       string `json:"name" fieldmask:",required"`
+		Title      string `json:"title"`
+		CreateTime int64  `json:"create_time" fieldmask:",output_only"`
+		Audit      *Audit `json:"audit" fieldmask:",output_only"`
+	}
+
+	dst := &Book{Name: "books/1", Title: "Go", CreateTime: 1}
+	src := &Book{Name: "books/1", Title: "Rust", CreateTime: 2, Audit: &Audit{CreatedBy: "me"}}
+	if err := fieldmask.New("*").Merge(dst, src); err != nil {
+		t.Fatalf("Merge() unexpected error: %v", err)
+	}
+
+	want := &Book{Name: "books/1", Title: "Rust", CreateTime: 1}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("Merge() = %+v, want %+v", dst, want)
+	}
+}
+
+func TestFieldMask_ValidateUpdate(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by"`
+	}
+
+	type Book struct {
+		Etag       string `json:"etag" fieldmask:",required"`
+		Title      string `json:"title"`
+		ISBN       string `json:"isbn" fieldmask:",immutable"`
+		CreateTime int64  `json:"create_time" fieldmask:",output_only"`
+		Audit      *Audit `json:"audit" fieldmask:",output_only"`
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		wantPaths []string
+		wantError error
+	}{
+		{
+			name: "writable fields",
+			mask: fieldmask.New("title", "etag"),
+		},
+		{
+			name: "wildcard",
+			mask: fieldmask.New("*"),
+		},
+		{
+			name: "immutable fields",
+			mask: fieldmask.New("isbn"),
+		},
+		{
+			name:      "output-only fields",
+			mask:      fieldmask.New("title", "create_time", "audit.created_by"),
+			wantPaths: []string{"create_time", "audit.created_by"},
+			wantError: fieldmask.ErrOutputOnly,
+		},
+		{
+			name:      "unknown fields",
+			mask:      fieldmask.New("titel"),
+			wantPaths: []string{"titel"},
+			wantError: fieldmask.ErrUnknownField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fieldmask.ValidateUpdateFor[Book](tt.mask)
+
+			if tt.wantError == nil {
+				if err != nil {
+					t.Errorf("ValidateUpdateFor() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("ValidateUpdateFor() error = %v, want %v", err, tt.wantError)
+			}
+			if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+			}
+			if err := fieldmask.ValidateFor[Book](tt.mask); errors.Is(err, fieldmask.ErrOutputOnly) {
+				t.Errorf("ValidateFor() error = %v, want no output-only error", err)
+			}
+		})
+	}
+}
+
+func TestRegisterFieldBehavior(t *testing.T) {
+	// Shelf stands for a type that cannot be tagged, such as generated code.
+	type Shelf struct {
+		ID         string `json:"id"`
+		Theme      string `json:"theme"`
+		UpdateTime int64  `json:"update_time"`
+	}
+
+	if err := fieldmask.RegisterFieldBehavior[Shelf]("ID", fieldmask.Required); err != nil {
+		t.Fatalf("RegisterFieldBehavior() unexpected error: %v", err)
+	}
+	if err := fieldmask.RegisterFieldBehavior[Shelf]("UpdateTime", fieldmask.OutputOnly); err != nil {
+		t.Fatalf("RegisterFieldBehavior() unexpected error: %v", err)
+	}
+
+	shelf := &Shelf{ID: "shelves/1", Theme: "fiction", UpdateTime: 1}
+	if err := fieldmask.New("theme").Apply(shelf); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if want := (&Shelf{ID: "shelves/1", Theme: "fiction"}); !reflect.DeepEqual(shelf, want) {
+		t.Errorf("Apply() = %+v, want %+v", shelf, want)
+	}
+
+	err := fieldmask.ValidateUpdateFor[Shelf](fieldmask.New("theme", "update_time"))
+	if !errors.Is(err, fieldmask.ErrOutputOnly) {
+		t.Errorf("ValidateUpdateFor() error = %v, want %v", err, fieldmask.ErrOutputOnly)
+	}
+
+	tests := []struct {
+		name      string
+		register  func() error
+		wantError error
+	}{
+		{
+			name:      "unknown field",
+			register:  func() error { return fieldmask.RegisterFieldBehavior[Shelf]("Missing", fieldmask.Required) },
+			wantError: fieldmask.ErrUnknownField,
+		},
+		{
+			name:      "non-struct type",
+			register:  func() error { return fieldmask.RegisterFieldBehavior[string]("ID", fieldmask.Required) },
+			wantError: fieldmask.ErrNoStruct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.register(); !errors.Is(err, tt.wantError) {
+				t.Errorf("RegisterFieldBehavior() error = %v, want %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestFieldBehavior_Project(t *testing.T) {
+	type Shelf struct {
+		ID    string `json:"id" fieldmask:",required"`
+		Theme string `json:"theme"`
+	}
+	type Book struct {
+		Name       string `json:"name" fieldmask:",required"`
+		Title      string `json:"title"`
+		CreateTime int64  `json:"create_time" fieldmask:",output_only"`
+		Shelf      *Shelf `json:"shelf"`
+		Sequel     *Book  `json:"sequel"`
+	}
+
+	newBook := func() *Book {
+		return &Book{
+			Name:       "books/1",
+			Title:      "Go",
+			CreateTime: 1,
+			Shelf:      &Shelf{ID: "shelves/1", Theme: "fiction"},
+			Sequel:     &Book{Name: "books/2", Title: "More Go", CreateTime: 2},
+		}
+	}
+
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+	}{
+		{
+			name: "required fields are kept",
+			mask: fieldmask.New("title"),
+		},
+		{
+			name: "selected output-only fields are copied",
+			mask: fieldmask.New("create_time", "sequel.create_time"),
+		},
+		{
+			name: "nested paths keep required fields of siblings",
+			mask: fieldmask.New("shelf.theme", "sequel.title"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.Project(tt.mask, newBook())
+			if err != nil {
+				t.Fatalf("Project() unexpected error: %v", err)
+			}
+
+			want := newBook()
+			if err := tt.mask.Apply(want); err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Project() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("cycle", func(t *testing.T) {
+		book := newBook()
+		book.Sequel.Sequel = book
+
+		got, err := fieldmask.Project(fieldmask.New("title"), book)
+		if err != nil {
+			t.Fatalf("Project() unexpected error: %v", err)
+		}
+		if got.Name != "books/1" || got.Title != "Go" || got.Sequel.Name != "books/2" || got.Sequel.Sequel != got {
+			t.Errorf("Project() = %+v, want required fields with the cycle preserved", got)
+		}
+	})
+}
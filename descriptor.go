@@ -1,163 +1,639 @@
 package fieldmask
 
 import (
+	"cmp"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 )
 
 const (
-	jsonTagSeparator = ","
-	jsonTagIgnore    = "-"
+	tagSeparator = ","
+	tagIgnore    = "-"
 )
 
 var (
 	descriptorCache sync.Map
 	zeroCache       sync.Map
+
+	// scalarDescriptor describes map values that are not walkable structs, such as scalars and opaque leaf types. It
+	// has no fields, so paths below a map key keep the entry as a whole, and diff and merge handle the entry whole.
+	scalarDescriptor = &typeDescriptor{}
 )
 
 type (
+	// typeDescriptor describes the fields of a struct type. It records whether the struct holds required or
+	// immutable fields, directly or through nested struct fields, so walkers know when a field cannot be handled as
+	// a whole. Fields excluded by a tag of "-" are recorded apart, since apply never clears them and project must
+	// copy them.
 	typeDescriptor struct {
-		fields map[string]*fieldDescriptor
+		fields       map[string]*fieldDescriptor
+		excluded     []*fieldDescriptor
+		foldCase     bool
+		hasRequired  bool
+		hasImmutable bool
+	}
+
+	// descriptorKey identifies a cached typeDescriptor. The same type is described differently by resolvers with
+	// different options, which are identified by their key.
+	descriptorKey struct {
+		typ      reflect.Type
+		resolver string
 	}
 
+	// visitKey identifies a pointer followed during apply. Cycles and shared values can only be reached through
+	// pointers, and the type is part of the key because a struct and its first field share the same address.
+	visitKey struct {
+		addr uintptr
+		typ  reflect.Type
+	}
+
+	// fieldDescriptor describes a field reachable by its tag. The index is a path through embedded structs for
+	// promoted fields, see field.
+	// The nested flag is set for fields holding a struct directly or through pointers, as opposed to containers.
 	fieldDescriptor struct {
-		tag   string
-		index []int
-		child *typeDescriptor
+		tag        string
+		index      []int
+		typ        reflect.Type
+		child      *typeDescriptor
+		nested     bool
+		required   bool
+		immutable  bool
+		outputOnly bool
+	}
+
+	// namedField is a struct field found by structFields along with its name and fieldmask tag options.
+	namedField struct {
+		reflect.StructField
+		name   string
+		tagged bool
+		opts   fieldOptions
 	}
 )
 
-// apply updates the struct fields based on the provided paths, zeroing out fields not specified in the path list.
-// It uses the visited map to handle circular references and avoids processing unaddressable values.
+// apply updates the struct fields based on the provided path trie, zeroing out fields not specified in it.
+// Pointers are dereferenced, and nil pointers are left untouched since there is nothing to retain.
+// Slices and arrays apply the same paths to every element, while maps treat the first path segment as a key.
+// A wildcard segment matches every field of a struct, every element of a slice or array, and every key of a map.
+// Values other than structs and string-keyed maps are leaves and are kept as a whole.
+// It uses the visited map to handle circular references through pointers and avoids processing unaddressable values.
 // Returns an error if any issue arises during recursive field processing.
-func (d *typeDescriptor) apply(value reflect.Value, paths []string, visited map[uintptr]bool) error {
-	if !value.CanAddr() {
+func (d *typeDescriptor) apply(value reflect.Value, paths *pathTrie, visited map[visitKey]bool) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+
+		key := visitKey{addr: value.Pointer(), typ: value.Type()}
+		if visited[key] {
+			return nil
+		}
+		visited[key] = true
+
+		return d.apply(value.Elem(), paths, visited)
+	case reflect.Slice, reflect.Array:
+		paths, keepAll := paths.elements()
+		if keepAll {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := d.apply(value.Index(i), paths, visited); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if !isKeyedMap(value.Type()) {
+			return nil
+		}
+		return d.applyMap(value, paths, visited)
+	case reflect.Struct:
+		if !value.CanAddr() {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	wildcard := paths.child(wildcardSegment)
+	keepAll := wildcard.isTerminal()
+	for tag, desc := range d.fields {
+		fieldValue, ok := desc.field(value, false)
+		if !ok || !fieldValue.CanSet() {
+			continue
+		}
+
+		// A path naming a field keeps it as a whole, even next to paths below it, like its canonical form.
+		sub := d.lookup(paths, tag)
+		if sub.isTerminal() || keepAll || desc.required {
+			continue
+		}
+
+		if desc.child == nil {
+			// Paths reaching below a leaf field keep the leaf as a whole.
+			if sub == nil {
+				fieldValue.Set(getZero(fieldValue.Type()))
+			}
+			continue
+		}
+
+		sub = mergeTries(sub, wildcard)
+		if sub == nil {
+			if !desc.holdsRequired() {
+				fieldValue.Set(getZero(fieldValue.Type()))
+				continue
+			}
+			sub = emptyTrie
+		}
+
+		if err := desc.child.apply(fieldValue, sub, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMap applies the path trie to a map keyed by strings, where the first segment of each path names a key or is
+// the wildcard segment matching every key. Entries not named by any path are deleted, and entries with nested paths
+// are copied into an addressable value, processed and stored back.
+func (d *typeDescriptor) applyMap(value reflect.Value, paths *pathTrie, visited map[visitKey]bool) error {
+	if value.IsNil() {
+		return nil
+	}
+
+	wildcard := paths.child(wildcardSegment)
+	if wildcard.isTerminal() {
 		return nil
 	}
 
-	addr := value.UnsafeAddr()
-	if visited[addr] {
+	for _, key := range value.MapKeys() {
+		sub := paths.child(key.String())
+		if sub.isTerminal() {
+			continue
+		}
+
+		sub = mergeTries(sub, wildcard)
+		if sub == nil {
+			value.SetMapIndex(key, reflect.Value{})
+			continue
+		}
+
+		elem := reflect.New(value.Type().Elem()).Elem()
+		elem.Set(value.MapIndex(key))
+		if err := d.apply(elem, sub, visited); err != nil {
+			return err
+		}
+		value.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+// prune zeroes the struct fields matched by the provided path trie, keeping every other field. It follows the same
+// traversal rules as apply: pointers are dereferenced, slices and arrays apply the paths to every element, maps treat
+// the first path segment as a key and delete matched entries, and paths reaching below a leaf field leave it untouched.
+func (d *typeDescriptor) prune(value reflect.Value, paths *pathTrie, visited map[visitKey]bool) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+
+		key := visitKey{addr: value.Pointer(), typ: value.Type()}
+		if visited[key] {
+			return nil
+		}
+		visited[key] = true
+
+		return d.prune(value.Elem(), paths, visited)
+	case reflect.Slice, reflect.Array:
+		paths, pruneAll := paths.elements()
+		if pruneAll {
+			if value.CanSet() {
+				value.Set(getZero(value.Type()))
+			}
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := d.prune(value.Index(i), paths, visited); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if !isKeyedMap(value.Type()) {
+			return nil
+		}
+		return d.pruneMap(value, paths, visited)
+	case reflect.Struct:
+		if !value.CanAddr() {
+			return nil
+		}
+	default:
 		return nil
 	}
-	visited[addr] = true
 
-	keepMap, nestedPaths := buildPathMaps(paths)
+	wildcard := paths.child(wildcardSegment)
+	pruneAll := wildcard.isTerminal()
 	for tag, desc := range d.fields {
-		fieldValue := value.FieldByIndex(desc.index)
-		if !fieldValue.CanSet() {
+		fieldValue, ok := desc.field(value, false)
+		if !ok || !fieldValue.CanSet() {
 			continue
 		}
 
-		_, keep := keepMap[tag]
-		if desc.child != nil {
-			if sub, ok := nestedPaths[tag]; ok {
-				if err := desc.child.apply(fieldValue, sub, visited); err != nil {
-					return err
-				}
+		if desc.required {
+			continue
+		}
+
+		sub := d.lookup(paths, tag)
+		switch {
+		case sub.isTerminal() || pruneAll:
+			if !desc.holdsRequired() {
+				fieldValue.Set(getZero(fieldValue.Type()))
 				continue
 			}
+			sub = wholeTrie
+		case desc.child == nil:
+			continue
+		default:
+			sub = mergeTries(sub, wildcard)
+			if sub == nil {
+				continue
+			}
+		}
+
+		if err := desc.child.prune(fieldValue, sub, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneMap prunes a map keyed by strings, deleting the entries named by the first segment of each path and pruning
+// entries with nested paths in an addressable copy that is stored back.
+func (d *typeDescriptor) pruneMap(value reflect.Value, paths *pathTrie, visited map[visitKey]bool) error {
+	if value.IsNil() {
+		return nil
+	}
+
+	wildcard := paths.child(wildcardSegment)
+	pruneAll := wildcard.isTerminal()
+	for _, key := range value.MapKeys() {
+		sub := paths.child(key.String())
+		if sub.isTerminal() || pruneAll {
+			value.SetMapIndex(key, reflect.Value{})
+			continue
+		}
+
+		sub = mergeTries(sub, wildcard)
+		if sub == nil {
+			continue
 		}
-		if !keep {
-			fieldValue.Set(getZero(fieldValue.Type()))
+
+		elem := reflect.New(value.Type().Elem()).Elem()
+		elem.Set(value.MapIndex(key))
+		if err := d.prune(elem, sub, visited); err != nil {
+			return err
 		}
+		value.SetMapIndex(key, elem)
 	}
 
 	return nil
 }
 
-// getTypeDescriptor retrieves or builds a typeDescriptor for a given reflect.Type, caching the result for future use.
-// It dereferences pointer types to their underlying element type and handles circular references during descriptor creation.
-// Returns the cached or newly built typeDescriptor, or an error if descriptor creation fails.
-func getTypeDescriptor(t reflect.Type) (*typeDescriptor, error) {
+// lookup returns the sub-trie of paths selecting the field named tag. Descriptors built by a case-insensitive
+// resolver fall back, when no segment equals tag exactly, to merging the sub-tries of every segment equal to tag under
+// Unicode case folding, except segments naming another field exactly, like fieldByName.
+func (d *typeDescriptor) lookup(paths *pathTrie, tag string) *pathTrie {
+	if sub := paths.child(tag); sub != nil || !d.foldCase {
+		return sub
+	}
+
+	var sub *pathTrie
+	if paths != nil {
+		for segment, child := range paths.children {
+			if _, exact := d.fields[segment]; !exact && strings.EqualFold(segment, tag) {
+				sub = mergeTries(sub, child)
+			}
+		}
+	}
+	return sub
+}
+
+// fieldByName returns the field named by a path segment. Descriptors built by a case-insensitive resolver fall back
+// to a field whose name is equal to the segment under Unicode case folding.
+func (d *typeDescriptor) fieldByName(segment string) (*fieldDescriptor, bool) {
+	if fd, ok := d.fields[segment]; ok || !d.foldCase {
+		return fd, ok
+	}
+
+	for tag, fd := range d.fields {
+		if strings.EqualFold(tag, segment) {
+			return fd, true
+		}
+	}
+	return nil, false
+}
+
+// field returns the field described by fd in the struct value v. Unlike reflect.Value.FieldByIndex, it does not
+// panic on a nil embedded pointer along the index path: when alloc is true and the pointer is settable, it is
+// allocated, otherwise field reports false.
+func (fd *fieldDescriptor) field(v reflect.Value, alloc bool) (reflect.Value, bool) {
+	if len(fd.index) == 1 {
+		return v.Field(fd.index[0]), true
+	}
+
+	for i, x := range fd.index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// getTypeDescriptor retrieves or builds a typeDescriptor for a given reflect.Type as named by the resolver r, caching
+// the result for future use. It dereferences pointer types to their underlying element type and handles
+// self-referential types during descriptor creation. Every descriptor built along the way is cached once the whole
+// graph is complete. Returns the cached or newly built typeDescriptor, or an error if descriptor creation fails.
+func getTypeDescriptor(t reflect.Type, r *Resolver) (*typeDescriptor, error) {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 
-	if cached, ok := descriptorCache.Load(t); ok {
+	if cached, ok := descriptorCache.Load(descriptorKey{typ: t, resolver: r.key}); ok {
 		return cached.(*typeDescriptor), nil
 	}
 
-	desc, err := buildDescriptor(t, map[reflect.Type]bool{})
+	building := map[reflect.Type]*typeDescriptor{}
+	desc, err := buildDescriptor(t, r, building)
 	if err != nil {
 		return nil, err
 	}
 
-	descriptorCache.Store(t, desc)
+	markNestedOptions(building)
+	for bt, bd := range building {
+		descriptorCache.LoadOrStore(descriptorKey{typ: bt, resolver: r.key}, bd)
+	}
 	return desc, nil
 }
 
-// buildDescriptor constructs a typeDescriptor for the given reflect.Type, including details for its exported fields.
-// It skips unexported fields and fields with a JSON tag set to "-".
-// Recursive calls are made for nested struct types, with circular references handled via the visited map.
-// Returns the constructed typeDescriptor or an error if a circular reference is detected.
-func buildDescriptor(t reflect.Type, visited map[reflect.Type]bool) (*typeDescriptor, error) {
-	if visited[t] {
-		return nil, &errCircularReference{reflectType: t}
+// markNestedOptions sets hasRequired and hasImmutable on the descriptors holding such fields, directly or through
+// nested struct fields. Flags are propagated until the graph is stable, which also settles self-referential types.
+func markNestedOptions(descs map[reflect.Type]*typeDescriptor) {
+	for changed := true; changed; {
+		changed = false
+		for _, d := range descs {
+			for _, fd := range d.fields {
+				if !d.hasRequired && (fd.required || fd.holdsRequired()) {
+					d.hasRequired, changed = true, true
+				}
+				if !d.hasImmutable && (fd.immutable || fd.holdsImmutable()) {
+					d.hasImmutable, changed = true, true
+				}
+			}
+		}
 	}
+}
 
-	visited[t] = true
+// holdsRequired reports whether the field holds a struct with required fields, which must not be cleared as a whole.
+func (fd *fieldDescriptor) holdsRequired() bool {
+	return fd.nested && fd.child.hasRequired
+}
 
-	desc := &typeDescriptor{fields: make(map[string]*fieldDescriptor, t.NumField())}
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if !field.IsExported() {
-			continue
-		}
+// holdsImmutable reports whether the field holds a struct with immutable fields, which must not be replaced as a
+// whole.
+func (fd *fieldDescriptor) holdsImmutable() bool {
+	return fd.nested && fd.child.hasImmutable
+}
 
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == jsonTagIgnore {
-			continue
-		}
+// buildDescriptor constructs a typeDescriptor for the given reflect.Type, including details for its exported fields
+// named by the resolver r. It skips unexported fields and fields whose tag is "-", and promotes the fields of
+// embedded structs following the rules of encoding/json, see structFields.
+// Recursive calls are made for nested struct types, including the elements of slices, arrays and string-keyed maps,
+// except for opaque types such as time.Time, which are leaves, see isLeafType.
+// Types already present in the building map are returned as-is, so self-referential types produce a finite
+// descriptor graph with back-references instead of infinite recursion. Completed descriptors from the cache are reused.
+func buildDescriptor(t reflect.Type, r *Resolver, building map[reflect.Type]*typeDescriptor) (*typeDescriptor, error) {
+	if desc, ok := building[t]; ok {
+		return desc, nil
+	}
 
-		tagName := parseJSONTag(jsonTag)
-		if tagName == "" {
-			tagName = field.Name
-		}
+	if cached, ok := descriptorCache.Load(descriptorKey{typ: t, resolver: r.key}); ok {
+		return cached.(*typeDescriptor), nil
+	}
 
+	fields, excluded, err := structFields(t, r)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &typeDescriptor{fields: make(map[string]*fieldDescriptor, len(fields)), foldCase: r.opts.CaseInsensitive}
+	for _, index := range excluded {
+		desc.excluded = append(desc.excluded, &fieldDescriptor{index: index})
+	}
+	building[t] = desc
+	for _, field := range fields {
 		fd := &fieldDescriptor{
-			tag:   tagName,
-			index: field.Index,
+			tag:        field.name,
+			index:      field.Index,
+			typ:        field.Type,
+			required:   field.opts.behavior&Required != 0,
+			immutable:  field.opts.immutable || field.opts.behavior&OutputOnly != 0,
+			outputOnly: field.opts.behavior&OutputOnly != 0,
 		}
 
-		if field.Type.Kind() == reflect.Struct || (field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct) {
-			ft := field.Type
-			if ft.Kind() == reflect.Ptr {
-				ft = ft.Elem()
-			}
-			child, err := buildDescriptor(ft, visited)
+		if field.opts.leaf {
+			desc.fields[field.name] = fd
+			continue
+		}
+
+		if ft, keyed := containerElem(field.Type); ft.Kind() == reflect.Struct && !isLeafType(ft) {
+			child, err := buildDescriptor(ft, r, building)
 			if err != nil {
 				return nil, err
 			}
 			fd.child = child
+			fd.nested = derefType(field.Type).Kind() == reflect.Struct
+		} else if keyed {
+			fd.child = scalarDescriptor
 		}
 
-		desc.fields[tagName] = fd
+		desc.fields[field.name] = fd
 	}
 	return desc, nil
 }
 
-// buildPathMaps processes the given paths to separate top-level keys and nested paths into respective maps.
-// It returns a map of top-level keys (`keepMap`) and a map of parent-to-child paths (`nestedPaths`).
-// Each key in `keepMap` represents a top-level path, and `nestedPaths` organizes sub-paths by their parent keys.
-func buildPathMaps(paths []string) (map[string]struct{}, map[string][]string) {
-	keepMap := make(map[string]struct{}, len(paths))
-	nestedPaths := make(map[string][]string)
+// structFields returns the fields of the struct type t as seen by encoding/json, named by their fieldmask tag alias or
+// else by the resolver r. Fields excluded by a fieldmask tag of "-", or by a tag of "-" read by the resolver, are left
+// out and their index paths are returned apart. Anonymous struct and *struct fields without a tag name are not fields
+// themselves: their fields are promoted to t, with an index path through the embedded fields. When several fields
+// share a name, the shallowest one wins, ties are broken in favor of the only field with a tag name, and remaining
+// conflicts hide the name altogether. Returns an error for invalid fieldmask tags.
+func structFields(t reflect.Type, r *Resolver) ([]namedField, [][]int, error) {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []namedField
+	var excluded [][]int
+	visited := map[reflect.Type]bool{}
+	for next := []embedded{{typ: t}}; len(next) > 0; {
+		current := next
+		next = nil
 
-	for _, p := range paths {
-		parts := strings.SplitN(p, pathSeparator, 2)
-		if len(parts) == 1 {
-			keepMap[parts[0]] = struct{}{}
-		} else {
-			nestedPaths[parts[0]] = append(nestedPaths[parts[0]], parts[1])
+		count := map[reflect.Type]int{}
+		for _, e := range current {
+			count[e.typ]++
 		}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				field := e.typ.Field(i)
+				ft := field.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if field.Anonymous {
+					if !field.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !field.IsExported() {
+					continue
+				}
+
+				name, opts, ignored, err := parseFieldmaskTag(field)
+				if err != nil {
+					return nil, nil, err
+				}
+				opts.behavior |= registeredBehavior(e.typ, field.Name)
+				if name == "" && !ignored {
+					name, ignored = r.tagName(field)
+				}
+				index := append(slices.Clone(e.index), i)
+				if ignored {
+					if field.IsExported() {
+						excluded = append(excluded, index)
+					}
+					continue
+				}
+
+				if name == "" && field.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+
+				tagged := name != ""
+				if !tagged {
+					name = r.opts.Naming.name(field.Name)
+				}
+				field.Index = index
+				fields = append(fields, namedField{StructField: field, name: name, tagged: tagged, opts: opts})
+
+				// A struct embedded more than once at the same depth conflicts with itself, like in encoding/json.
+				if count[e.typ] > 1 {
+					fields = append(fields, fields[len(fields)-1])
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(fields, func(a, b namedField) int {
+		return cmp.Or(
+			strings.Compare(a.name, b.name),
+			cmp.Compare(len(a.Index), len(b.Index)),
+			compareBool(b.tagged, a.tagged),
+		)
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if j-i == 1 || len(fields[i].Index) != len(fields[i+1].Index) || fields[i].tagged != fields[i+1].tagged {
+			dominant = append(dominant, fields[i])
+		}
+		i = j
+	}
+
+	slices.SortFunc(dominant, func(a, b namedField) int {
+		return slices.Compare(a.Index, b.Index)
+	})
+	return dominant, excluded, nil
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// containerElem unwraps pointers, slices, arrays and string-keyed maps around t and returns the element type found
+// underneath. It also reports whether a string-keyed map was unwrapped along the way. A map with any other key type
+// stops the descent and is returned as the element, so walkers keep it as a leaf, see isKeyedMap.
+func containerElem(t reflect.Type) (reflect.Type, bool) {
+	keyed := false
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Map:
+			if !isKeyedMap(t) {
+				return t, keyed
+			}
+			keyed = true
+			t = t.Elem()
+		default:
+			return t, keyed
+		}
+	}
+}
+
+// isKeyedMap reports whether t is a map keyed by strings. Only those maps are addressed by path segments, and maps
+// with any other key type are leaves, even below a string-keyed map.
+func isKeyedMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// derefType unwraps the pointers around t.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
-	return keepMap, nestedPaths
+	return t
 }
 
-// parseJSONTag extracts the field name from a JSON struct tag, ignoring additional options like "omitempty".
-func parseJSONTag(tag string) string {
-	if idx := strings.Index(tag, jsonTagSeparator); idx != -1 {
+// parseTagName extracts the field name from a struct tag value, ignoring additional options like "omitempty".
+func parseTagName(tag string) string {
+	if idx := strings.Index(tag, tagSeparator); idx != -1 {
 		return tag[:idx]
 	}
 	return tag
@@ -2,6 +2,7 @@ package fieldmask
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -56,12 +57,12 @@ func TestTypeDescriptor_Apply(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			desc, err := getTypeDescriptor(reflect.TypeOf(tt.input))
+			desc, err := getTypeDescriptor(reflect.TypeOf(tt.input), defaultResolver)
 			if err != nil {
 				t.Fatalf("failed to get descriptor: %v", err)
 			}
 			value := reflect.ValueOf(&tt.input).Elem()
-			if err := desc.apply(value, tt.paths, map[uintptr]bool{}); err != nil {
+			if err := desc.apply(value, newPathTrie(tt.paths), map[visitKey]bool{}); err != nil {
 				t.Fatalf("apply failed: %v", err)
 			}
 			if !reflect.DeepEqual(tt.input, tt.expected) {
@@ -78,6 +79,11 @@ func TestTypeDescriptor_GetTypeDescriptor(t *testing.T) {
 	type Recursive struct {
 		Child *Recursive
 	}
+	type Mutual struct {
+		Back *struct {
+			Mutual *Mutual
+		}
+	}
 
 	tests := []struct {
 		name        string
@@ -89,15 +95,18 @@ func TestTypeDescriptor_GetTypeDescriptor(t *testing.T) {
 			input: reflect.TypeOf(TestStruct{}),
 		},
 		{
-			name:        "circular reference",
-			input:       reflect.TypeOf(Recursive{}),
-			expectError: true,
+			name:  "self-referential structure",
+			input: reflect.TypeOf(Recursive{}),
+		},
+		{
+			name:  "mutually recursive structures",
+			input: reflect.TypeOf(Mutual{}),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := getTypeDescriptor(tt.input)
+			_, err := getTypeDescriptor(tt.input, defaultResolver)
 			if (err != nil) != tt.expectError {
 				t.Errorf("getTypeDescriptor() error = %v, wantError = %v", err, tt.expectError)
 			}
@@ -105,6 +114,20 @@ func TestTypeDescriptor_GetTypeDescriptor(t *testing.T) {
 	}
 }
 
+func TestTypeDescriptor_GetTypeDescriptor_BackReference(t *testing.T) {
+	type Recursive struct {
+		Child *Recursive
+	}
+
+	desc, err := getTypeDescriptor(reflect.TypeOf(Recursive{}), defaultResolver)
+	if err != nil {
+		t.Fatalf("failed to get descriptor: %v", err)
+	}
+	if got := desc.fields["Child"].child; got != desc {
+		t.Errorf("child descriptor = %p, want back-reference %p", got, desc)
+	}
+}
+
 func TestTypeDescriptor_BuildDescriptor(t *testing.T) {
 	type Nested struct {
 		SubField string `json:"subfield"`
@@ -127,6 +150,18 @@ func TestTypeDescriptor_BuildDescriptor(t *testing.T) {
 			input:    reflect.TypeOf(TestStruct{}),
 			expected: []string{"field1", "field2", "nested"},
 		},
+		{
+			name:     "promote embedded struct fields",
+			input:    reflect.TypeOf(struct{ TestStruct }{}),
+			expected: []string{"field1", "field2", "nested"},
+		},
+		{
+			name: "keep tagged embedded struct",
+			input: reflect.TypeOf(struct {
+				TestStruct `json:"base"`
+			}{}),
+			expected: []string{"base"},
+		},
 		{
 			name:        "no exported fields",
 			input:       reflect.TypeOf(struct{ unexported int }{}),
@@ -137,7 +172,7 @@ func TestTypeDescriptor_BuildDescriptor(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			desc, err := buildDescriptor(tt.input, map[reflect.Type]bool{})
+			desc, err := buildDescriptor(tt.input, defaultResolver, map[reflect.Type]*typeDescriptor{})
 			if (err != nil) != tt.expectError {
 				t.Errorf("buildDescriptor() error = %v, wantError %v", err, tt.expectError)
 			}
@@ -146,6 +181,7 @@ func TestTypeDescriptor_BuildDescriptor(t *testing.T) {
 				for tag := range desc.fields {
 					gotTags = append(gotTags, tag)
 				}
+				slices.Sort(gotTags)
 				if !reflect.DeepEqual(gotTags, tt.expected) {
 					t.Errorf("fields mismatch, got %v, want %v", gotTags, tt.expected)
 				}
@@ -154,43 +190,158 @@ func TestTypeDescriptor_BuildDescriptor(t *testing.T) {
 	}
 }
 
-func TestTypeDescriptor_BuildPathMaps(t *testing.T) {
+func TestTypeDescriptor_StructFields(t *testing.T) {
+	type Base struct {
+		ID      string `json:"id"`
+		Created int    `json:"created_at"`
+	}
+	type Audit struct {
+		ID      string `json:"id"`
+		Updated int    `json:"updated_at"`
+	}
+	type Meta struct {
+		Name string
+	}
+	type named struct {
+		Name string `json:"name"`
+	}
+	type deep struct {
+		Base
+	}
+	type label struct {
+		Label string `json:"Name"`
+	}
+
 	tests := []struct {
-		name          string
-		input         []string
-		expectedKeep  map[string]struct{}
-		expectedPaths map[string][]string
+		name     string
+		input    reflect.Type
+		expected map[string][]int
 	}{
 		{
-			name:         "simple paths",
-			input:        []string{"field1", "field2.subfield"},
-			expectedKeep: map[string]struct{}{"field1": {}},
-			expectedPaths: map[string][]string{
-				"field2": {"subfield"},
-			},
+			name: "promote value and pointer embedded fields",
+			input: reflect.TypeOf(struct {
+				Base
+				*Meta
+				Title string `json:"title"`
+			}{}),
+			expected: map[string][]int{"id": {0, 0}, "created_at": {0, 1}, "Name": {1, 0}, "title": {2}},
+		},
+		{
+			name: "promote fields of unexported embedded struct",
+			input: reflect.TypeOf(struct {
+				named
+			}{}),
+			expected: map[string][]int{"name": {0, 0}},
+		},
+		{
+			name: "shallower field wins",
+			input: reflect.TypeOf(struct {
+				deep
+				Audit
+			}{}),
+			expected: map[string][]int{"id": {1, 0}, "updated_at": {1, 1}, "created_at": {0, 0, 1}},
+		},
+		{
+			// Built at run time, since vet rejects the conflicting tags in a struct literal.
+			name: "conflicting fields at the same depth are hidden",
+			input: reflect.StructOf([]reflect.StructField{
+				{Name: "Base", Type: reflect.TypeOf(Base{}), Anonymous: true},
+				{Name: "Audit", Type: reflect.TypeOf(Audit{}), Anonymous: true},
+			}),
+			expected: map[string][]int{"created_at": {0, 1}, "updated_at": {1, 1}},
 		},
 		{
-			name:          "empty paths",
-			input:         nil,
-			expectedKeep:  map[string]struct{}{},
-			expectedPaths: map[string][]string{},
+			name: "tagged field wins a conflict at the same depth",
+			input: reflect.TypeOf(struct {
+				Meta
+				label
+			}{}),
+			expected: map[string][]int{"Name": {1, 0}},
+		},
+		{
+			name: "direct field shadows promoted field",
+			input: reflect.TypeOf(struct {
+				Meta
+				Name int
+			}{}),
+			expected: map[string][]int{"Name": {1}},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			keepMap, nestedPaths := buildPathMaps(tt.input)
-			if !reflect.DeepEqual(keepMap, tt.expectedKeep) {
-				t.Errorf("keepMap mismatch. got %v, want %v", keepMap, tt.expectedKeep)
+			fields, _, err := structFields(tt.input, defaultResolver)
+			if err != nil {
+				t.Fatalf("structFields() unexpected error: %v", err)
+			}
+
+			got := map[string][]int{}
+			for _, field := range fields {
+				got[field.name] = field.Index
 			}
-			if !reflect.DeepEqual(nestedPaths, tt.expectedPaths) {
-				t.Errorf("nestedPaths mismatch. got %v, want %v", nestedPaths, tt.expectedPaths)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("structFields() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTypeDescriptor_ContainerElem(t *testing.T) {
+	type Item struct {
+		Field string
+	}
+
+	tests := []struct {
+		name          string
+		input         reflect.Type
+		expected      reflect.Type
+		expectedKeyed bool
+	}{
+		{
+			name:     "struct",
+			input:    reflect.TypeOf(Item{}),
+			expected: reflect.TypeOf(Item{}),
+		},
+		{
+			name:     "slice of struct pointers",
+			input:    reflect.TypeOf([]*Item{}),
+			expected: reflect.TypeOf(Item{}),
+		},
+		{
+			name:     "array of slices of structs",
+			input:    reflect.TypeOf([2][]Item{}),
+			expected: reflect.TypeOf(Item{}),
+		},
+		{
+			name:          "map of struct pointers",
+			input:         reflect.TypeOf(map[string]*Item{}),
+			expected:      reflect.TypeOf(Item{}),
+			expectedKeyed: true,
+		},
+		{
+			name:          "map of strings",
+			input:         reflect.TypeOf(map[string]string{}),
+			expected:      reflect.TypeOf(""),
+			expectedKeyed: true,
+		},
+		{
+			name:     "map with non-string keys",
+			input:    reflect.TypeOf(map[int]Item{}),
+			expected: reflect.TypeOf(map[int]Item{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, keyed := containerElem(tt.input)
+			if got != tt.expected || keyed != tt.expectedKeyed {
+				t.Errorf("containerElem(%v) = %v, %v, want %v, %v", tt.input, got, keyed, tt.expected, tt.expectedKeyed)
 			}
 		})
 	}
 }
 
-func TestTypeDescriptor_ParseJSONTag(t *testing.T) {
+func TestTypeDescriptor_ParseTagName(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
@@ -225,9 +376,9 @@ func TestTypeDescriptor_ParseJSONTag(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseJSONTag(tt.input)
+			got := parseTagName(tt.input)
 			if got != tt.expected {
-				t.Errorf("parseJSONTag(%q) = %q, want %q", tt.input, got, tt.expected)
+				t.Errorf("parseTagName(%q) = %q, want %q", tt.input, got, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,171 @@
+package fieldmask
+
+import (
+	"reflect"
+	"slices"
+)
+
+// diffKey identifies a pair of pointers followed during diff, guarding against cycles in either value.
+type diffKey struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+// Diff compares two values of the same struct type and returns a FieldMask with the minimal set of paths whose values
+// differ, or nil when they are equal. Nested structs are compared field by field, and a nested struct that is new or
+// removed on one side collapses to the path of its parent field. Slices of different lengths are reported as a whole,
+// entries of string-keyed maps are reported by key, and maps with any other key type are reported as a whole. Paths are
+// sorted, and only fields known to the type descriptor are compared.
+func Diff(a, b any) (*FieldMask, error) {
+	return defaultResolver.Diff(a, b)
+}
+
+// Diff compares two values of the same struct type and returns a FieldMask with the minimal set of paths whose values
+// differ, named by the resolver. See Diff.
+func (r *Resolver) Diff(a, b any) (*FieldMask, error) {
+	av, td, err := r.structPointer(a)
+	if err != nil {
+		return nil, err
+	}
+
+	bv, _, err := r.structPointer(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if av.Type() != bv.Type() {
+		return nil, ErrTypeMismatch
+	}
+
+	paths, _ := td.diff(av, bv, make(map[diffKey]bool))
+	slices.Sort(paths)
+	return New(paths...), nil
+}
+
+// diff compares a and b, which must be of the same type, and returns the relative paths whose values differ.
+// It reports whole when the values differ in a way no sub-path can express, such as a nil pointer on one side or
+// slices of different lengths, in which case the caller records its own path instead.
+func (d *typeDescriptor) diff(a, b reflect.Value, visited map[diffKey]bool) ([]string, bool) {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return nil, a.IsNil() != b.IsNil()
+		}
+
+		key := diffKey{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+		if visited[key] {
+			return nil, false
+		}
+		visited[key] = true
+
+		return d.diff(a.Elem(), b.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return nil, true
+		}
+
+		var paths []string
+		for i := 0; i < a.Len(); i++ {
+			sub, whole := d.diff(a.Index(i), b.Index(i), visited)
+			if whole {
+				return nil, true
+			}
+			paths = append(paths, sub...)
+		}
+		return removeDuplicatePaths(paths), false
+	case reflect.Map:
+		if !isKeyedMap(a.Type()) {
+			return nil, !reflect.DeepEqual(a.Interface(), b.Interface())
+		}
+		return d.diffMap(a, b, visited), false
+	case reflect.Struct:
+		// Structs reached through the scalar descriptor are opaque map values, compared as a whole.
+		if d == scalarDescriptor {
+			return nil, !leafEqual(a, b)
+		}
+	default:
+		return nil, !leafEqual(a, b)
+	}
+
+	var paths []string
+	for tag, desc := range d.fields {
+		af, bf := diffField(desc, a), diffField(desc, b)
+		if desc.child == nil {
+			if !leafEqual(af, bf) {
+				paths = append(paths, tag)
+			}
+			continue
+		}
+
+		sub, whole := desc.child.diff(af, bf, visited)
+		if whole {
+			paths = append(paths, tag)
+			continue
+		}
+		for _, s := range sub {
+			paths = append(paths, tag+pathSeparator+s)
+		}
+	}
+
+	return paths, false
+}
+
+// diffMap compares two maps keyed by strings. Keys present on only one side are reported as a whole, and entries
+// present on both sides are compared recursively.
+func (d *typeDescriptor) diffMap(a, b reflect.Value, visited map[diffKey]bool) []string {
+	var paths []string
+	for _, key := range a.MapKeys() {
+		name := key.String()
+		be := b.MapIndex(key)
+		if !be.IsValid() {
+			paths = append(paths, name)
+			continue
+		}
+
+		sub, whole := d.diff(a.MapIndex(key), be, visited)
+		if whole {
+			paths = append(paths, name)
+			continue
+		}
+		for _, s := range sub {
+			paths = append(paths, name+pathSeparator+s)
+		}
+	}
+
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			paths = append(paths, key.String())
+		}
+	}
+
+	return paths
+}
+
+// leafEqual reports whether the leaves a and b, which must be of the same type, are equal. Pointers are compared by
+// the values they point to, and types with an Equal method taking their own type, such as time.Time, are compared with
+// it, so equal instants with different monotonic readings or locations compare equal. Other values are compared with
+// reflect.DeepEqual.
+func leafEqual(a, b reflect.Value) bool {
+	for a.Kind() == reflect.Ptr {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+
+	t := a.Type()
+	if m, ok := t.MethodByName("Equal"); ok && m.Type.NumIn() == 2 && m.Type.In(1) == t &&
+		m.Type.NumOut() == 1 && m.Type.Out(0).Kind() == reflect.Bool {
+		return m.Func.Call([]reflect.Value{a, b})[0].Bool()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// diffField returns the field described by desc in the struct value v, or its zero value when it is promoted through a
+// nil embedded pointer.
+func diffField(desc *fieldDescriptor, v reflect.Value) reflect.Value {
+	if field, ok := desc.field(v, false); ok {
+		return field
+	}
+	return getZero(desc.typ)
+}
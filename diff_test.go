@@ -0,0 +1,166 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestDiff(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type User struct {
+		Name    string                    `json:"name"`
+		Tags    []string                  `json:"tags"`
+		Profile Profile                   `json:"profile"`
+		Extra   *Profile                  `json:"extra"`
+		Items   []Item                    `json:"items"`
+		Attrs   map[string]*Item          `json:"attrs"`
+		Labels  map[string]string         `json:"labels"`
+		Scores  map[string]map[int]string `json:"scores"`
+	}
+
+	type Other struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name    string
+		a       any
+		b       any
+		want    *fieldmask.FieldMask
+		wantErr error
+	}{
+		{
+			name: "equal values",
+			a:    &User{Name: "a", Profile: Profile{Age: 1}, Tags: []string{"x"}},
+			b:    &User{Name: "a", Profile: Profile{Age: 1}, Tags: []string{"x"}},
+			want: nil,
+		},
+		{
+			name: "top-level and nested leaves",
+			a:    &User{Name: "a", Tags: []string{"x"}, Profile: Profile{Age: 1, Bio: "bio"}},
+			b:    &User{Name: "b", Tags: []string{"y"}, Profile: Profile{Age: 2, Bio: "bio"}},
+			want: fieldmask.New("name", "profile.age", "tags"),
+		},
+		{
+			name: "new nested struct collapses to parent",
+			a:    &User{},
+			b:    &User{Extra: &Profile{Age: 1, Bio: "bio"}},
+			want: fieldmask.New("extra"),
+		},
+		{
+			name: "removed nested struct collapses to parent",
+			a:    &User{Extra: &Profile{Age: 1}},
+			b:    &User{},
+			want: fieldmask.New("extra"),
+		},
+		{
+			name: "changed nested pointer fields",
+			a:    &User{Extra: &Profile{Age: 1, Bio: "a"}},
+			b:    &User{Extra: &Profile{Age: 1, Bio: "b"}},
+			want: fieldmask.New("extra.bio"),
+		},
+		{
+			name: "slice elements with equal lengths",
+			a:    &User{Items: []Item{{ID: "a", Price: 1}, {ID: "b", Price: 2}}},
+			b:    &User{Items: []Item{{ID: "a", Price: 1}, {ID: "b", Price: 3}}},
+			want: fieldmask.New("items.price"),
+		},
+		{
+			name: "slices with different lengths",
+			a:    &User{Items: []Item{{ID: "a"}}},
+			b:    &User{Items: []Item{{ID: "a"}, {ID: "b"}}},
+			want: fieldmask.New("items"),
+		},
+		{
+			name: "map entries",
+			a: &User{
+				Attrs:  map[string]*Item{"color": {ID: "c", Price: 1}, "size": {ID: "s"}},
+				Labels: map[string]string{"env": "dev", "team": "core"},
+			},
+			b: &User{
+				Attrs:  map[string]*Item{"color": {ID: "c", Price: 2}, "weight": {ID: "w"}},
+				Labels: map[string]string{"env": "prod", "team": "core"},
+			},
+			want: fieldmask.New("attrs.color.price", "attrs.size", "attrs.weight", "labels.env"),
+		},
+		{
+			name: "map with non-string keys below a map key as a whole",
+			a:    &User{Scores: map[string]map[int]string{"a": {1: "one"}, "b": {2: "two"}}},
+			b:    &User{Scores: map[string]map[int]string{"a": {1: "uno"}, "b": {2: "two"}}},
+			want: fieldmask.New("scores.a"),
+		},
+		{
+			name:    "nil input",
+			a:       nil,
+			b:       &User{},
+			wantErr: fieldmask.ErrNilInput,
+		},
+		{
+			name:    "different types",
+			a:       &User{},
+			b:       &Other{},
+			wantErr: fieldmask.ErrTypeMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.Diff(tt.a, tt.b)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Diff() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Diff() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+
+			if err := got.Merge(tt.a, tt.b); err != nil {
+				t.Fatalf("Merge() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(tt.a, tt.b) {
+				t.Errorf("Merge() with diff = %+v, want %+v", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestDiff_Cycle(t *testing.T) {
+	type Node struct {
+		Name string `json:"name"`
+		Next *Node  `json:"next"`
+	}
+
+	a := &Node{Name: "a"}
+	a.Next = a
+	b := &Node{Name: "b"}
+	b.Next = b
+
+	got, err := fieldmask.Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff() unexpected error: %v", err)
+	}
+	if want := fieldmask.New("name"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
@@ -5,9 +5,9 @@
 //
 // Key Features:
 //   - Selective field updates using dot notation paths
-//   - Support for nested structs, pointers, and complex types
-//   - JSON tag compatibility
-//   - Built-in protection against circular references
+//   - Support for nested structs, pointers, slices, arrays, maps, and complex types
+//   - JSON tag compatibility, including fields promoted from embedded structs
+//   - Support for self-referential types and protection against value cycles
 //   - High performance through internal caching
 //   - Zero external dependencies
 //
@@ -45,27 +45,167 @@
 //	    log.Fatal(err)
 //	}
 //
+// Paths through slices and arrays apply to every element, while paths through
+// maps keyed by strings name a key, or use "*" to match every key:
+//
+//	mask := fieldmask.New("items.price", "attrs.color.value", "labels.*.value")
+//
+// The "*" segment is a wildcard: it matches every field of a struct, every
+// element of a slice or array, and every key of a map. It is honored by Apply,
+// HasPath and RemovePaths:
+//
+//	mask := fieldmask.New("profile.*", "items.*.id", "*.id")
+//
+// To strip a few fields and keep everything else, use Prune instead:
+//
+//	mask := fieldmask.New("password_hash", "internal.notes")
+//	if err := mask.Prune(user); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Masked updates, such as PATCH handlers driven by an update mask, copy only
+// the listed paths from a source onto a destination of the same type:
+//
+//	mask := fieldmask.New("email", "profile.bio")
+//	if err := mask.Merge(stored, request); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Project returns a filtered copy instead of mutating its argument, which makes
+// it safe for shared or cached values:
+//
+//	filtered, err := fieldmask.Project(mask, cachedUser)
+//
+// Diff computes the mask of paths whose values differ between two values of
+// the same type, which is useful for audit logs and outgoing update masks:
+//
+//	mask, err := fieldmask.Diff(before, after)
+//
+// A sparse JSON body can be turned into a mask of the leaf paths it contains,
+// optionally resolved against a Go type so unknown keys are rejected:
+//
+//	mask, err := fieldmask.FromJSONFor[User](body)
+//
 // Path Management:
 //
 //	mask := fieldmask.New("user.profile.name", "user.email")
+//	mask, err := fieldmask.Parse("user.profile.name,user.email")
+//	s := mask.Format()                             // "user.email,user.profile.name"
+//	mask, err = fieldmask.ParseSelector("user(email,profile/name)")
+//	s = mask.FormatSelector()                      // "user(email,profile/name)"
 //	exists := mask.HasPath("user.email")           // Check specific path
 //	exists = mask.HashAny("user.name", "user.id")  // Check multiple paths
 //	mask.RemovePaths("user.email")                 // Remove paths
 //	paths := mask.GetPaths()                       // Get all paths
+//	mask.Canonicalize()                            // Sort and drop redundant paths
+//	same := mask.Equal(other)                      // Compare canonical forms
+//
+// Masks implement the JSON and text marshaling interfaces. The default JSON
+// form is an object holding the paths; WireProto3 selects the proto3 mapping of
+// google.protobuf.FieldMask, a string with lowerCamelCase segments. Decoding
+// accepts either form:
+//
+//	mask := &fieldmask.FieldMask{Paths: paths, Wire: fieldmask.WireProto3}
+//	data, err := json.Marshal(mask)                // "displayName,profile.birthDate"
+//
+// MarshalProto and UnmarshalProto encode the google.protobuf.FieldMask message
+// in the protobuf binary format, writing lowerCamelCase segments in snake_case,
+// without depending on a protobuf library.
+//
+// Masks can be combined with set operations that follow the same prefix
+// semantics, returning normalized masks:
+//
+//	visible := requested.Intersect(allowedForRole) // "profile" ∩ "profile.age" = "profile.age"
+//	fields := defaults.Union(requested)
+//	writable := updateMask.Subtract(immutable)
+//
+// Containment checks answer whether every requested path is allowed:
+//
+//	if !allowed.Covers(requested) {
+//	    denied := allowed.UncoveredPaths(requested)
+//	}
 //
 // Performance Optimizations:
 //
 // The package implements internal caching for type descriptors and zero values,
 // ensuring optimal performance for repeated operations on the same types.
+// Each FieldMask also builds a prefix tree of its paths on first use, which
+// serves lookups, set operations and Apply without rescanning the path list.
 // The caching is thread-safe and handles concurrent access properly.
 //
+// Masks applied repeatedly to the same type can be compiled into a Plan, which
+// validates the paths once and applies them without building path maps. A
+// plan does not allocate unless it follows more than eight pointers in a
+// single call:
+//
+//	plan, err := fieldmask.Compile[User](mask)
+//	err = plan.Apply(user)
+//
 // Error Handling:
 //
-// The Apply method returns errors in the following cases:
+// The Apply, Prune and Merge methods return errors in the following cases:
 //   - Nil input
 //   - Non-pointer input
 //   - Non-struct input
-//   - Circular references in nested structs
+//   - Mismatched destination and source types (Merge only)
+//
+// Self-referential types such as trees are supported: Apply only descends as
+// deep as the paths in the mask go, and value cycles are visited once.
+//
+// The fieldmask struct tag declares masking policy separately from the JSON
+// shape: a path alias, "-" to exclude a field from masking so it is always
+// kept, and the options leaf, required and immutable:
+//
+//	type User struct {
+//	    ID   string `json:"id" fieldmask:",required,immutable"`
+//	    Meta Meta   `json:"meta" fieldmask:",leaf"`
+//	}
+//
+// Struct types implementing json.Marshaler or encoding.TextMarshaler, such as
+// time.Time, are opaque leaves masked as a whole, like fields tagged leaf.
+// RegisterLeaf declares other types opaque:
+//
+//	fieldmask.RegisterLeaf[sql.NullString]()
+//
+// Field behaviors follow AIP-203: identity fields, tagged
+// `fieldmask:",required"`, are always kept by Apply and Project, and
+// output-only fields, tagged `fieldmask:",output_only"`, are never written by
+// Merge. Types that cannot be tagged use RegisterFieldBehavior. ValidateUpdate
+// rejects update masks naming output-only fields, but accepts immutable ones,
+// which clients may send back unchanged:
+//
+//	if err := fieldmask.ValidateUpdateFor[Book](mask); errors.Is(err, fieldmask.ErrOutputOnly) {
+//	    // reject the request
+//	}
+//
+// Paths name fields by their json tag, or their Go name when untagged. A
+// Resolver configures other tag keys, a snake_case or lowerCamelCase fallback
+// and case-insensitive matching, and provides Apply, ApplyStrict, Prune,
+// Merge, Project, Validate and Diff with those names:
+//
+//	r := fieldmask.NewResolver(fieldmask.Options{TagKeys: []string{"db"}})
+//	err := r.Apply(mask, &user)
+//
+// Fields of embedded structs without a JSON name are promoted to the parent
+// like encoding/json does, so a User embedding a Base with an "id" field is
+// addressed as "id". Fields behind a nil embedded pointer are left untouched.
+//
+// Apply ignores paths that do not match any field. To reject them, validate the
+// mask against the target type first, or use ApplyStrict, which validates
+// before modifying anything. The returned error joins one field processing
+// error per invalid path, which FieldProcessingPaths lists:
+//
+//	if err := mask.ApplyStrict(user); err != nil {
+//	    invalid := fieldmask.FieldProcessingPaths(err)
+//	    // respond with 400 INVALID_ARGUMENT
+//	}
+//
+// Registration:
+//
+// RegisterLeaf and RegisterFieldBehavior describe types that cannot be
+// tagged. Call them during initialization, before masks are applied to the
+// types they describe: each registration clears the cached type descriptors,
+// and plans compiled beforehand keep the descriptors they were compiled with.
 //
 // Thread Safety:
 //
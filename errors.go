@@ -7,8 +7,14 @@ import (
 )
 
 var (
-	ErrNilInput = errors.New("cannot apply fieldmask to nil struct")
-	ErrNoStruct = errors.New("input is not a struct")
+	ErrNilInput         = errors.New("cannot apply fieldmask to nil struct")
+	ErrNoStruct         = errors.New("input is not a struct")
+	ErrTypeMismatch     = errors.New("destination and source types differ")
+	ErrNoObject         = errors.New("input is not a JSON object")
+	ErrUnknownField     = errors.New("unknown field")
+	ErrIrreversiblePath = errors.New("path cannot be converted between snake_case and lowerCamelCase")
+	ErrOutputOnly       = errors.New("field is output only")
+	ErrInvalidKey       = errors.New("JSON key cannot be used as a path segment")
 )
 
 type errUnexpectedKind struct {
@@ -24,29 +30,87 @@ func IsUnexpectedKindError(err error) bool {
 	return errors.As(err, &errUnexpectedKind)
 }
 
-type errCircularReference struct {
-	reflectType reflect.Type
-}
-
-func (e *errCircularReference) Error() string {
-	return fmt.Sprintf("circular reference detected for type %v", e.reflectType.Name())
-}
-
+// IsCircularReferenceError always returns false. Self-referential types are described with back-references, so no
+// operation fails because of a circular reference anymore.
+//
+// Deprecated: self-referential types are supported and there is no circular reference error to check for.
 func IsCircularReferenceError(err error) bool {
-	var errCircularReference *errCircularReference
-	return errors.As(err, &errCircularReference)
+	return false
 }
 
 type errFieldProcessing struct {
 	fieldName string
+	segment   string
 	err       error
 }
 
 func (e *errFieldProcessing) Error() string {
+	if e.segment != "" {
+		return fmt.Sprintf("failed to process field %s at segment %q: %v", e.fieldName, e.segment, e.err)
+	}
 	return fmt.Sprintf("failed to process field %s: %v", e.fieldName, e.err)
 }
 
+func (e *errFieldProcessing) Unwrap() error {
+	return e.err
+}
+
 func IsFieldProcessingError(err error) bool {
 	var errFieldProcessing *errFieldProcessing
 	return errors.As(err, &errFieldProcessing)
 }
+
+type errInvalidTag struct {
+	field  string
+	option string
+}
+
+func (e *errInvalidTag) Error() string {
+	return fmt.Sprintf("invalid fieldmask tag option %q on field %s", e.option, e.field)
+}
+
+func IsInvalidTagError(err error) bool {
+	var errInvalidTag *errInvalidTag
+	return errors.As(err, &errInvalidTag)
+}
+
+type errSyntax struct {
+	offset int
+	msg    string
+}
+
+func (e *errSyntax) Error() string {
+	return fmt.Sprintf("syntax error at offset %d: %s", e.offset, e.msg)
+}
+
+func IsSyntaxError(err error) bool {
+	var errSyntax *errSyntax
+	return errors.As(err, &errSyntax)
+}
+
+// SyntaxErrorOffset returns the byte offset in the parsed input of the syntax error found in err's tree.
+// It reports false if err does not contain a syntax error.
+func SyntaxErrorOffset(err error) (int, bool) {
+	var errSyntax *errSyntax
+	if !errors.As(err, &errSyntax) {
+		return 0, false
+	}
+	return errSyntax.offset, true
+}
+
+// FieldProcessingPaths returns the field paths of every field processing error found in err's tree, in order.
+func FieldProcessingPaths(err error) []string {
+	var paths []string
+	switch e := err.(type) {
+	case nil:
+	case *errFieldProcessing:
+		paths = append(paths, e.fieldName)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			paths = append(paths, FieldProcessingPaths(inner)...)
+		}
+	case interface{ Unwrap() error }:
+		paths = FieldProcessingPaths(e.Unwrap())
+	}
+	return paths
+}
@@ -2,14 +2,18 @@ package fieldmask
 
 import (
 	"fmt"
-	"reflect"
 	"slices"
 	"strings"
 )
 
 // FieldMask enables selective field updates by specifying dot-notation paths.
 type FieldMask struct {
+	// Paths lists the dot-notation paths of the mask. Lookups are served by an index built lazily from Paths and
+	// rebuilt whenever Paths changes.
 	Paths []string `json:"paths"`
+
+	// Wire selects the form produced by the JSON and text marshalers. The zero value keeps the JSON object form.
+	Wire WireFormat `json:"-"`
 }
 
 func (f *FieldMask) String() string {
@@ -36,20 +40,39 @@ func (f *FieldMask) Normalize() {
 	f.Paths = normalized
 }
 
-// HasPath checks if the specified path is in the FieldMask.
-func (f *FieldMask) HasPath(path string) bool {
+// Canonicalize rewrites the FieldMask into its canonical form: whitespace around path segments is trimmed, empty and
+// duplicate paths are removed, paths covered by an ancestor such as "profile.age" next to "profile" are dropped, and
+// the remaining paths are sorted. Semantically identical masks share the same canonical form and String output,
+// which makes it suitable for comparisons and cache keys.
+func (f *FieldMask) Canonicalize() {
 	if f.IsEmpty() {
-		return false
+		return
 	}
 
-	prefix := path + "."
-	for _, p := range f.Paths {
-		if p == path || strings.HasPrefix(p, prefix) {
-			return true
-		}
+	canonical := trimPaths(f.Paths)
+	canonical = removeEmptyPaths(canonical)
+	canonical = removeDuplicatePaths(canonical)
+	canonical = removeRedundantPaths(canonical)
+	slices.Sort(canonical)
+	f.Paths = canonical
+}
+
+// Equal reports whether the FieldMask and other have the same canonical form. Nil and empty masks are equal.
+func (f *FieldMask) Equal(other *FieldMask) bool {
+	a := &FieldMask{Paths: f.GetPaths()}
+	b := &FieldMask{Paths: other.GetPaths()}
+	a.Canonicalize()
+	b.Canonicalize()
+	return slices.Equal(a.Paths, b.Paths)
+}
+
+// HasPath checks if the specified path is in the FieldMask. A wildcard segment on either side matches any segment.
+func (f *FieldMask) HasPath(path string) bool {
+	if f.IsEmpty() {
+		return false
 	}
 
-	return false
+	return f.trie().hasPath(path)
 }
 
 // HashAny checks if any of the given paths are present in the FieldMask and returns true if at least one match is found.
@@ -76,51 +99,45 @@ func (f *FieldMask) GetPaths() []string {
 	return slices.Clone(f.Paths)
 }
 
-// RemovePaths deletes the specified paths from the FieldMask.
+// RemovePaths deletes the specified paths from the FieldMask. A wildcard segment in a removed path matches any segment.
 func (f *FieldMask) RemovePaths(paths ...string) {
 	if f.IsEmpty() {
 		return
 	}
 
-	for _, p := range paths {
-		newPaths := make([]string, 0, len(f.Paths))
-		prefix := p + "."
-		for _, existing := range f.Paths {
-			if existing == p || strings.HasPrefix(existing, prefix) {
-				continue
-			}
+	if len(paths) == 0 {
+		return
+	}
+
+	removed := newPathTrie(paths)
+	newPaths := make([]string, 0, len(f.Paths))
+	for _, existing := range f.Paths {
+		if !removed.covers(existing, false) {
 			newPaths = append(newPaths, existing)
 		}
-		f.Paths = newPaths
 	}
+	f.Paths = newPaths
 }
 
-// Apply zeros to all struct fields except those specified in f.Paths.
+// Apply zeros to all struct fields except those specified in f.Paths. Fields tagged `fieldmask:",required"` or
+// excluded with `fieldmask:"-"` are always kept. A path naming a struct keeps it as a whole, even next to paths below
+// it, so "profile" and "profile.age" keep the same fields as "profile" alone, like their canonical form. A path
+// reaching below a leaf field, such as "name.first" for a string field, keeps the leaf as a whole; such paths are
+// rejected by Validate and ApplyStrict.
 func (f *FieldMask) Apply(i any) error {
-	if f.IsEmpty() {
-		return nil
-	}
-
-	if i == nil {
-		return ErrNilInput
-	}
-
-	v := reflect.ValueOf(i)
-	if v.Kind() != reflect.Ptr || v.IsNil() {
-		return ErrNilInput
-	}
-
-	v = v.Elem()
-	if v.Kind() != reflect.Struct {
-		return ErrNoStruct
-	}
+	return defaultResolver.Apply(f, i)
+}
 
-	td, err := getTypeDescriptor(v.Type())
-	if err != nil {
-		return err
-	}
+// ApplyStrict behaves like Apply but first validates every path in f.Paths against the type of i. If any path cannot
+// be resolved, it returns the validation error, which carries the offending paths, without modifying i.
+func (f *FieldMask) ApplyStrict(i any) error {
+	return defaultResolver.ApplyStrict(f, i)
+}
 
-	return td.apply(v, f.Paths, make(map[uintptr]bool))
+// Prune zeros only the struct fields specified in f.Paths, keeping everything else. It is the inverse of Apply.
+// Required fields are kept as well.
+func (f *FieldMask) Prune(i any) error {
+	return defaultResolver.Prune(f, i)
 }
 
 // New creates a FieldMask with the given paths. Returns nil for empty input.
@@ -1,6 +1,7 @@
 package fieldmask_test
 
 import (
+	"fmt"
 	"testing"
 
 	"go.g3deon.com/fieldmask"
@@ -23,3 +24,128 @@ func BenchmarkFieldmask_Apply(b *testing.B) {
 		fm.Apply(s)
 	}
 }
+
+func BenchmarkFieldmask_HasPath(b *testing.B) {
+	paths := make([]string, 0, 500)
+	for i := range 500 {
+		paths = append(paths, fmt.Sprintf("group%d.field%d", i%50, i))
+	}
+	fm := fieldmask.New(paths...)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		fm.HasPath("group49.field499")
+	}
+}
+
+func BenchmarkPlan_Apply(b *testing.B) {
+	type testStruct struct {
+		Field1 string
+		Field2 int
+		Field3 bool
+		Field4 float64
+	}
+	plan, err := fieldmask.Compile[testStruct](fieldmask.New("Field1", "Field2", "Field3"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	s := &testStruct{}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		*s = testStruct{}
+		plan.Apply(s)
+	}
+}
+
+func BenchmarkFieldmask_Apply_Nested(b *testing.B) {
+	type profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+	type item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+	type user struct {
+		Name    string   `json:"name"`
+		Email   string   `json:"email"`
+		Profile *profile `json:"profile"`
+		Items   []item   `json:"items"`
+	}
+	fm := fieldmask.New("name", "profile.age", "items.*.id")
+	s := &user{
+		Name:    "John",
+		Email:   "john@example.com",
+		Profile: &profile{Age: 30, Bio: "Developer"},
+		Items:   []item{{ID: "a", Price: 1}, {ID: "b", Price: 2}, {ID: "c", Price: 3}},
+	}
+	p := *s.Profile
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		*s.Profile = p
+		fm.Apply(s)
+	}
+}
+
+func BenchmarkPlan_Apply_Nested(b *testing.B) {
+	type profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+	type item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+	type user struct {
+		Name    string   `json:"name"`
+		Email   string   `json:"email"`
+		Profile *profile `json:"profile"`
+		Items   []item   `json:"items"`
+	}
+	plan, err := fieldmask.Compile[user](fieldmask.New("name", "profile.age", "items.*.id"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	s := &user{
+		Name:    "John",
+		Email:   "john@example.com",
+		Profile: &profile{Age: 30, Bio: "Developer"},
+		Items:   []item{{ID: "a", Price: 1}, {ID: "b", Price: 2}, {ID: "c", Price: 3}},
+	}
+	p := *s.Profile
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		*s.Profile = p
+		plan.Apply(s)
+	}
+}
+
+func BenchmarkPlan_Apply_Map(b *testing.B) {
+	type item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+	type resource struct {
+		Name  string          `json:"name"`
+		Attrs map[string]item `json:"attrs"`
+	}
+
+	plan, err := fieldmask.Compile[resource](fieldmask.New("attrs.*.id"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	s := &resource{Attrs: map[string]item{"a": {ID: "a", Price: 1}, "b": {ID: "b", Price: 2}}}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		plan.Apply(s)
+	}
+}
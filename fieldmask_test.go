@@ -104,6 +104,94 @@ func TestFieldMask_Normalize(t *testing.T) {
 	}
 }
 
+func TestFieldMask_Canonicalize(t *testing.T) {
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want *fieldmask.FieldMask
+	}{
+		{
+			name: "nil mask",
+			mask: nil,
+			want: nil,
+		},
+		{
+			name: "sorted paths",
+			mask: &fieldmask.FieldMask{Paths: []string{"name", "email", "age"}},
+			want: &fieldmask.FieldMask{Paths: []string{"age", "email", "name"}},
+		},
+		{
+			name: "redundant descendants removed",
+			mask: &fieldmask.FieldMask{Paths: []string{"profile.age", "profile", "name", "items.*", "items.id"}},
+			want: &fieldmask.FieldMask{Paths: []string{"items.*", "name", "profile"}},
+		},
+		{
+			name: "whitespace trimmed",
+			mask: &fieldmask.FieldMask{Paths: []string{" name ", "profile . age", "name", "  "}},
+			want: &fieldmask.FieldMask{Paths: []string{"name", "profile.age"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mask.Canonicalize()
+
+			if !reflect.DeepEqual(tt.mask, tt.want) {
+				t.Errorf("Canonicalize() = %v, want %v", tt.mask, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Equal(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  *fieldmask.FieldMask
+		other *fieldmask.FieldMask
+		want  bool
+	}{
+		{
+			name:  "nil and empty",
+			mask:  nil,
+			other: &fieldmask.FieldMask{},
+			want:  true,
+		},
+		{
+			name:  "same paths in different order",
+			mask:  fieldmask.New("name", "profile.age"),
+			other: fieldmask.New("profile.age", "name"),
+			want:  true,
+		},
+		{
+			name:  "redundant descendants",
+			mask:  fieldmask.New("profile", "profile.age"),
+			other: fieldmask.New(" profile "),
+			want:  true,
+		},
+		{
+			name:  "different paths",
+			mask:  fieldmask.New("profile"),
+			other: fieldmask.New("profile.age"),
+			want:  false,
+		},
+		{
+			name:  "empty and non-empty",
+			mask:  fieldmask.New(),
+			other: fieldmask.New("name"),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.Equal(tt.other)
+			if got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFieldMask_IsEmpty(t *testing.T) {
 	tests := []struct {
 		name string
@@ -181,6 +269,30 @@ func TestFieldMask_HasPath(t *testing.T) {
 			path: "field1",
 			want: false,
 		},
+		{
+			name: "wildcard in mask matches any field",
+			mask: fieldmask.New("field1.*"),
+			path: "field1.subfield",
+			want: true,
+		},
+		{
+			name: "leading wildcard in mask",
+			mask: fieldmask.New("*.id"),
+			path: "field1.id",
+			want: true,
+		},
+		{
+			name: "leading wildcard in mask with different leaf",
+			mask: fieldmask.New("*.id"),
+			path: "field1.name",
+			want: false,
+		},
+		{
+			name: "wildcard in path",
+			mask: fieldmask.New("field1.subfield"),
+			path: "*.subfield",
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -312,6 +424,18 @@ func TestFieldMask_RemovePaths(t *testing.T) {
 			},
 			want: &fieldmask.FieldMask{Paths: make([]string, 0)},
 		},
+		{
+			name:  "remove wildcard paths",
+			mask:  fieldmask.New("field1.a", "field1.b.c", "field2.a", "field3"),
+			paths: []string{"field1.*", "*.a"},
+			want:  fieldmask.New("field3"),
+		},
+		{
+			name:  "wildcard in mask is only removed by wildcard",
+			mask:  fieldmask.New("*.id", "field1"),
+			paths: []string{"field2"},
+			want:  fieldmask.New("*.id", "field1"),
+		},
 		{
 			name:  "remove from empty mask",
 			mask:  fieldmask.New(),
@@ -367,6 +491,40 @@ func TestFieldMask_Apply(t *testing.T) {
 		NoTag      int
 	}
 
+	type Node struct {
+		Name   string `json:"name"`
+		Value  int    `json:"value"`
+		Parent *Node  `json:"parent"`
+		Child  *Node  `json:"child"`
+	}
+
+	type LineItem struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type Order struct {
+		ID     string       `json:"id"`
+		Items  []LineItem   `json:"items"`
+		Refs   []*LineItem  `json:"refs"`
+		Fixed  [2]LineItem  `json:"fixed"`
+		Groups [][]LineItem `json:"groups"`
+	}
+
+	type Attr struct {
+		Value string `json:"value"`
+		Unit  string `json:"unit"`
+	}
+
+	type Resource struct {
+		Name   string                    `json:"name"`
+		Labels map[string]Attr           `json:"labels"`
+		Attrs  map[string]*Attr          `json:"attrs"`
+		Tags   map[string]string         `json:"tags"`
+		Counts map[int]Attr              `json:"counts"`
+		Scores map[string]map[int]string `json:"scores"`
+	}
+
 	type NestedMixedStruct struct {
 		JSONField   string `json:"json_field"`
 		NoTagField  string
@@ -494,6 +652,207 @@ func TestFieldMask_Apply(t *testing.T) {
 				Field2: "",
 			},
 		},
+		{
+			name: "path below top-level leaf keeps the leaf",
+			mask: fieldmask.New("field2.length"),
+			input: &ComplexStruct{
+				Field1: NestedStruct{Subfield: "value"},
+				Field2: "test",
+			},
+			want: &ComplexStruct{
+				Field2: "test",
+			},
+		},
+		{
+			name:  "apply mask through non-nil pointer",
+			mask:  fieldmask.New("nested.subfield"),
+			input: &PointerNestedStruct{Nested: &NestedStruct{Subfield: "sub-value"}, Field: "value"},
+			want:  &PointerNestedStruct{Nested: &NestedStruct{Subfield: "sub-value"}, Field: ""},
+		},
+		{
+			name:  "apply mask with parent and sub path keeps whole parent",
+			mask:  fieldmask.New("nested", "nested.with_tag"),
+			input: &NestedMixedStruct{Nested: MixedTagsStruct{WithTag: "a", WithoutTag: "b", NoTag: 1}, JSONField: "c"},
+			want:  &NestedMixedStruct{Nested: MixedTagsStruct{WithTag: "a", WithoutTag: "b", NoTag: 1}},
+		},
+		{
+			name:  "apply mask on first nested field sharing the parent address",
+			mask:  fieldmask.New("nested.with_tag"),
+			input: &NestedMixedStruct{Nested: MixedTagsStruct{WithTag: "a", WithoutTag: "b", NoTag: 1}, JSONField: "c"},
+			want:  &NestedMixedStruct{Nested: MixedTagsStruct{WithTag: "a"}},
+		},
+		{
+			name: "apply mask on self-referential type",
+			mask: fieldmask.New("name", "child.name", "child.child.value"),
+			input: &Node{
+				Name:   "root",
+				Value:  1,
+				Parent: &Node{Name: "parent"},
+				Child: &Node{
+					Name:  "child",
+					Value: 2,
+					Child: &Node{Name: "grandchild", Value: 3},
+				},
+			},
+			want: &Node{
+				Name: "root",
+				Child: &Node{
+					Name:  "child",
+					Child: &Node{Value: 3},
+				},
+			},
+		},
+		{
+			name: "apply mask on self-referential value cycle",
+			mask: fieldmask.New("name", "child.child.child.name"),
+			input: func() *Node {
+				n := &Node{Name: "loop", Value: 1}
+				n.Child = n
+				return n
+			}(),
+			want: func() *Node {
+				n := &Node{Name: "loop"}
+				n.Child = n
+				return n
+			}(),
+		},
+		{
+			name: "apply mask through slice of structs",
+			mask: fieldmask.New("id", "items.price"),
+			input: &Order{
+				ID:    "o1",
+				Items: []LineItem{{ID: "a", Price: 1}, {ID: "b", Price: 2}},
+				Refs:  []*LineItem{{ID: "c", Price: 3}},
+			},
+			want: &Order{
+				ID:    "o1",
+				Items: []LineItem{{Price: 1}, {Price: 2}},
+			},
+		},
+		{
+			name: "apply mask through slice of struct pointers",
+			mask: fieldmask.New("refs.id"),
+			input: &Order{
+				ID:   "o1",
+				Refs: []*LineItem{{ID: "a", Price: 1}, nil, {ID: "b", Price: 2}},
+			},
+			want: &Order{
+				Refs: []*LineItem{{ID: "a"}, nil, {ID: "b"}},
+			},
+		},
+		{
+			name: "apply mask through array and nested slices",
+			mask: fieldmask.New("fixed.price", "groups.id"),
+			input: &Order{
+				Fixed:  [2]LineItem{{ID: "a", Price: 1}, {ID: "b", Price: 2}},
+				Groups: [][]LineItem{{{ID: "c", Price: 3}}, {{ID: "d", Price: 4}}},
+			},
+			want: &Order{
+				Fixed:  [2]LineItem{{Price: 1}, {Price: 2}},
+				Groups: [][]LineItem{{{ID: "c"}}, {{ID: "d"}}},
+			},
+		},
+		{
+			name:  "apply mask keeps whole slice",
+			mask:  fieldmask.New("items"),
+			input: &Order{ID: "o1", Items: []LineItem{{ID: "a", Price: 1}}},
+			want:  &Order{Items: []LineItem{{ID: "a", Price: 1}}},
+		},
+		{
+			name: "apply mask on specific map key",
+			mask: fieldmask.New("attrs.color.value", "labels.env"),
+			input: &Resource{
+				Name:   "r1",
+				Labels: map[string]Attr{"env": {Value: "prod", Unit: "u"}, "team": {Value: "core"}},
+				Attrs:  map[string]*Attr{"color": {Value: "red", Unit: "rgb"}, "size": {Value: "xl"}},
+			},
+			want: &Resource{
+				Labels: map[string]Attr{"env": {Value: "prod", Unit: "u"}},
+				Attrs:  map[string]*Attr{"color": {Value: "red"}},
+			},
+		},
+		{
+			name: "apply mask on every map key",
+			mask: fieldmask.New("labels.*.value", "attrs.*"),
+			input: &Resource{
+				Labels: map[string]Attr{"env": {Value: "prod", Unit: "u"}, "team": {Value: "core", Unit: "v"}},
+				Attrs:  map[string]*Attr{"color": {Value: "red", Unit: "rgb"}},
+			},
+			want: &Resource{
+				Labels: map[string]Attr{"env": {Value: "prod"}, "team": {Value: "core"}},
+				Attrs:  map[string]*Attr{"color": {Value: "red", Unit: "rgb"}},
+			},
+		},
+		{
+			name: "apply mask on map of scalars",
+			mask: fieldmask.New("tags.env", "tags.missing"),
+			input: &Resource{
+				Tags: map[string]string{"env": "prod", "team": "core"},
+			},
+			want: &Resource{
+				Tags: map[string]string{"env": "prod"},
+			},
+		},
+		{
+			name: "apply mask keeps map with non-string keys as a whole",
+			mask: fieldmask.New("counts.value"),
+			input: &Resource{
+				Name:   "r1",
+				Counts: map[int]Attr{1: {Value: "one", Unit: "u"}},
+			},
+			want: &Resource{
+				Counts: map[int]Attr{1: {Value: "one", Unit: "u"}},
+			},
+		},
+		{
+			name: "apply mask keeps map with non-string keys below a map key as a whole",
+			mask: fieldmask.New("scores.a.1"),
+			input: &Resource{
+				Name:   "r1",
+				Scores: map[string]map[int]string{"a": {1: "one", 2: "two"}, "b": {3: "three"}},
+			},
+			want: &Resource{
+				Scores: map[string]map[int]string{"a": {1: "one", 2: "two"}},
+			},
+		},
+		{
+			name:  "apply mask with wildcard keeps every field of nested struct",
+			mask:  fieldmask.New("nested.*"),
+			input: &NestedMixedStruct{JSONField: "a", Nested: MixedTagsStruct{WithTag: "b", NoTag: 1}},
+			want:  &NestedMixedStruct{Nested: MixedTagsStruct{WithTag: "b", NoTag: 1}},
+		},
+		{
+			name: "apply mask with leading wildcard descends into every nested struct",
+			mask: fieldmask.New("*.with_tag", "*.Field1"),
+			input: &NestedMixedStruct{
+				JSONField:   "a",
+				Nested:      MixedTagsStruct{WithTag: "b", AnotherTag: "c"},
+				NoTagNested: NoTagStruct{Field1: "d", Field2: "e"},
+			},
+			want: &NestedMixedStruct{
+				Nested:      MixedTagsStruct{WithTag: "b"},
+				NoTagNested: NoTagStruct{Field1: "d"},
+			},
+		},
+		{
+			name:  "apply mask with top-level wildcard keeps everything",
+			mask:  fieldmask.New("*"),
+			input: &TestStruct{Field1: "x", Field2: "y"},
+			want:  &TestStruct{Field1: "x", Field2: "y"},
+		},
+		{
+			name: "apply mask with element wildcard on slice",
+			mask: fieldmask.New("items.*.id", "refs.*"),
+			input: &Order{
+				ID:    "o1",
+				Items: []LineItem{{ID: "a", Price: 1}, {ID: "b", Price: 2}},
+				Refs:  []*LineItem{{ID: "c", Price: 3}},
+			},
+			want: &Order{
+				Items: []LineItem{{ID: "a"}, {ID: "b"}},
+				Refs:  []*LineItem{{ID: "c", Price: 3}},
+			},
+		},
 		{
 			name: "handle nil pointer in nested structure",
 			mask: fieldmask.New("nested.subfield"),
@@ -557,6 +916,20 @@ func TestFieldMask_Apply_Panics(t *testing.T) {
 				return &i
 			},
 		},
+		{
+			name:        "nil embedded pointer",
+			mask:        fieldmask.New("name"),
+			description: "Should not panic when a promoted field sits behind a nil embedded pointer",
+			setupObj: func() interface{} {
+				type Base struct {
+					ID string `json:"id"`
+				}
+				return &struct {
+					*Base
+					Name string `json:"name"`
+				}{Name: "John"}
+			},
+		},
 		{
 			name:        "empty struct pointer",
 			mask:        fieldmask.New("nonexistent"),
@@ -580,3 +953,248 @@ func TestFieldMask_Apply_Panics(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldMask_Apply_Embedded(t *testing.T) {
+	type Base struct {
+		ID        string `json:"id"`
+		CreatedAt int    `json:"created_at"`
+	}
+
+	type Audit struct {
+		UpdatedBy string `json:"updated_by"`
+	}
+
+	type User struct {
+		Base
+		*Audit
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name  string
+		mask  *fieldmask.FieldMask
+		input *User
+		want  *User
+	}{
+		{
+			name:  "promoted fields are addressed by their JSON name",
+			mask:  fieldmask.New("id", "updated_by"),
+			input: &User{Base: Base{ID: "1", CreatedAt: 2}, Audit: &Audit{UpdatedBy: "admin"}, Name: "John"},
+			want:  &User{Base: Base{ID: "1"}, Audit: &Audit{UpdatedBy: "admin"}},
+		},
+		{
+			name:  "fields behind nil embedded pointer are skipped",
+			mask:  fieldmask.New("created_at"),
+			input: &User{Base: Base{ID: "1", CreatedAt: 2}, Name: "John"},
+			want:  &User{Base: Base{CreatedAt: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mask.ApplyStrict(tt.input); err != nil {
+				t.Fatalf("ApplyStrict() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.input, tt.want) {
+				t.Errorf("ApplyStrict() = %+v, want %+v", tt.input, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Prune(t *testing.T) {
+	type Internal struct {
+		Notes string `json:"notes"`
+		Score int    `json:"score"`
+	}
+
+	type Item struct {
+		ID     string `json:"id"`
+		Secret string `json:"secret"`
+	}
+
+	type Account struct {
+		Name         string           `json:"name"`
+		PasswordHash string           `json:"password_hash"`
+		Internal     Internal         `json:"internal"`
+		Extra        *Internal        `json:"extra"`
+		Items        []Item           `json:"items"`
+		Meta         map[string]*Item `json:"meta"`
+	}
+
+	newAccount := func() *Account {
+		return &Account{
+			Name:         "alice",
+			PasswordHash: "hash",
+			Internal:     Internal{Notes: "note", Score: 1},
+			Extra:        &Internal{Notes: "extra", Score: 2},
+			Items:        []Item{{ID: "a", Secret: "s1"}, {ID: "b", Secret: "s2"}},
+			Meta:         map[string]*Item{"x": {ID: "x", Secret: "s3"}, "y": {ID: "y", Secret: "s4"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		input     any
+		want      any
+		wantError bool
+	}{
+		{
+			name:  "prune empty mask",
+			mask:  fieldmask.New(),
+			input: newAccount(),
+			want:  newAccount(),
+		},
+		{
+			name:  "prune top-level and nested fields",
+			mask:  fieldmask.New("password_hash", "internal.notes", "extra.score"),
+			input: newAccount(),
+			want: func() *Account {
+				a := newAccount()
+				a.PasswordHash = ""
+				a.Internal.Notes = ""
+				a.Extra.Score = 0
+				return a
+			}(),
+		},
+		{
+			name:  "prune through slices and maps",
+			mask:  fieldmask.New("items.secret", "meta.*.secret", "meta.y"),
+			input: newAccount(),
+			want: func() *Account {
+				a := newAccount()
+				a.Items = []Item{{ID: "a"}, {ID: "b"}}
+				a.Meta = map[string]*Item{"x": {ID: "x"}}
+				return a
+			}(),
+		},
+		{
+			name:  "prune with wildcard",
+			mask:  fieldmask.New("internal.*", "items.*"),
+			input: newAccount(),
+			want: func() *Account {
+				a := newAccount()
+				a.Internal = Internal{}
+				a.Items = nil
+				return a
+			}(),
+		},
+		{
+			name:  "prune path below leaf leaves it untouched",
+			mask:  fieldmask.New("name.first", "unknown"),
+			input: newAccount(),
+			want:  newAccount(),
+		},
+		{
+			name:      "prune nil input",
+			mask:      fieldmask.New("name"),
+			input:     nil,
+			wantError: true,
+		},
+		{
+			name:      "prune non-struct input",
+			mask:      fieldmask.New("name"),
+			input:     new(string),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mask.Prune(tt.input)
+
+			if tt.wantError {
+				if err == nil {
+					t.Error("Prune() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Prune() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(tt.input, tt.want) {
+				t.Errorf("Prune() = %+v, want %+v", tt.input, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_ApplyStrict(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type User struct {
+		Name    string  `json:"name"`
+		Email   string  `json:"email"`
+		Profile Profile `json:"profile"`
+	}
+
+	newUser := func() *User {
+		return &User{Name: "John", Email: "john@example.com", Profile: Profile{Age: 30, Bio: "Developer"}}
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		input     any
+		want      any
+		wantPaths []string
+		wantError bool
+	}{
+		{
+			name:  "empty mask",
+			mask:  fieldmask.New(),
+			input: newUser(),
+			want:  newUser(),
+		},
+		{
+			name:  "valid paths",
+			mask:  fieldmask.New("name", "profile.age"),
+			input: newUser(),
+			want:  &User{Name: "John", Profile: Profile{Age: 30}},
+		},
+		{
+			name:      "unknown path leaves input untouched",
+			mask:      fieldmask.New("name", "profile.agee"),
+			input:     newUser(),
+			want:      newUser(),
+			wantPaths: []string{"profile.agee"},
+			wantError: true,
+		},
+		{
+			name:      "nil input",
+			mask:      fieldmask.New("name"),
+			input:     nil,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mask.ApplyStrict(tt.input)
+
+			if tt.wantError {
+				if err == nil {
+					t.Error("ApplyStrict() expected error, got nil")
+				}
+				if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+					t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+				}
+			} else if err != nil {
+				t.Errorf("ApplyStrict() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(tt.input, tt.want) {
+				t.Errorf("ApplyStrict() = %+v, want %+v", tt.input, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package fieldmask
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"weak"
+)
+
+type (
+	// pathTrie is a prefix tree of path segments. A node is terminal when a path ends at it. Tries are never modified
+	// once built, so they can be shared by concurrent readers.
+	pathTrie struct {
+		children map[string]*pathTrie
+		terminal bool
+	}
+
+	// pathIndex caches the trie built from the paths of a FieldMask, along with a copy of the paths it was built from.
+	pathIndex struct {
+		paths []string
+		root  *pathTrie
+	}
+)
+
+var (
+	// emptyTrie selects nothing. Walkers descend with it into a field they would clear as a whole when the field
+	// holds required fields to keep.
+	emptyTrie = &pathTrie{}
+	// wholeTrie selects every field. Walkers descend with it into a field they would prune or replace as a whole
+	// when the field holds required or immutable fields to skip.
+	wholeTrie = newPathTrie([]string{wildcardSegment})
+
+	// pathIndexes maps weak pointers to masks to their pathIndex. Entries are kept outside of FieldMask so masks can
+	// be copied and compared like plain values, and are deleted once their mask is garbage collected.
+	pathIndexes sync.Map
+)
+
+// trie returns the prefix tree of f.Paths, building it on first use. The cached trie is rebuilt whenever f.Paths
+// no longer holds the paths it was built from, including after elements are edited in place.
+func (f *FieldMask) trie() *pathTrie {
+	key := weak.Make(f)
+	if cached, ok := pathIndexes.Load(key); ok {
+		if idx := cached.(*pathIndex); slices.Equal(idx.paths, f.Paths) {
+			return idx.root
+		}
+	}
+
+	root := newPathTrie(f.Paths)
+	if _, loaded := pathIndexes.Swap(key, &pathIndex{paths: slices.Clone(f.Paths), root: root}); !loaded {
+		runtime.AddCleanup(f, func(key weak.Pointer[FieldMask]) { pathIndexes.Delete(key) }, key)
+	}
+	return root
+}
+
+// newPathTrie builds a prefix tree from paths.
+func newPathTrie(paths []string) *pathTrie {
+	root := &pathTrie{}
+	for _, path := range paths {
+		node := root
+		for segment := range strings.SplitSeq(path, pathSeparator) {
+			next, ok := node.children[segment]
+			if !ok {
+				if node.children == nil {
+					node.children = make(map[string]*pathTrie)
+				}
+				next = &pathTrie{}
+				node.children[segment] = next
+			}
+			node = next
+		}
+		node.terminal = true
+	}
+	return root
+}
+
+// child returns the node reached from t through segment, or nil. It is safe to call on a nil trie.
+func (t *pathTrie) child(segment string) *pathTrie {
+	if t == nil {
+		return nil
+	}
+	return t.children[segment]
+}
+
+// isTerminal reports whether a path ends at t. It is safe to call on a nil trie.
+func (t *pathTrie) isTerminal() bool {
+	return t != nil && t.terminal
+}
+
+// hasPath reports whether the segments of path lead to a node of the trie, that is, whether path is equal to or an
+// ancestor of a path in the trie. A wildcard segment on either side matches any segment.
+func (t *pathTrie) hasPath(path string) bool {
+	segment, rest, nested := strings.Cut(path, pathSeparator)
+	reaches := func(node *pathTrie) bool {
+		return node != nil && (!nested || node.hasPath(rest))
+	}
+
+	if segment == wildcardSegment {
+		for _, node := range t.children {
+			if reaches(node) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return reaches(t.children[segment]) || reaches(t.children[wildcardSegment])
+}
+
+// covers reports whether a path in the trie is equal to or an ancestor of path. A wildcard segment in the trie matches
+// any segment, while a wildcard segment in path only matches a wildcard. When strict is set, a path in the trie
+// identical to path does not count, so only other paths covering it are considered.
+func (t *pathTrie) covers(path string, strict bool) bool {
+	segment, rest, nested := strings.Cut(path, pathSeparator)
+	reaches := func(node *pathTrie, strict bool) bool {
+		if node == nil {
+			return false
+		}
+		if node.terminal && (nested || !strict) {
+			return true
+		}
+		return nested && node.covers(rest, strict)
+	}
+
+	if reaches(t.children[segment], strict) {
+		return true
+	}
+	return segment != wildcardSegment && reaches(t.children[wildcardSegment], false)
+}
+
+// intersect appends to paths the intersections of path with the paths in the trie, prefixed by prefix. Segments are
+// matched pairwise with wildcards keeping the more specific segment, and when one path is an ancestor of the other
+// the descendant is kept. Children are visited in sorted order.
+func (t *pathTrie) intersect(path, prefix string, paths []string) []string {
+	if t.terminal {
+		return append(paths, joinPath(prefix, path))
+	}
+
+	segment, rest, nested := strings.Cut(path, pathSeparator)
+	for _, childSegment := range t.sortedSegments() {
+		matched := segment
+		switch {
+		case childSegment == segment || childSegment == wildcardSegment:
+		case segment == wildcardSegment:
+			matched = childSegment
+		default:
+			continue
+		}
+
+		node := t.children[childSegment]
+		if nested {
+			paths = node.intersect(rest, joinPath(prefix, matched), paths)
+		} else {
+			paths = node.collect(joinPath(prefix, matched), paths)
+		}
+	}
+	return paths
+}
+
+// collect appends the paths ending at or below t to paths, prefixed by prefix, visiting children in sorted order.
+func (t *pathTrie) collect(prefix string, paths []string) []string {
+	if t.terminal {
+		paths = append(paths, prefix)
+	}
+	for _, segment := range t.sortedSegments() {
+		paths = t.children[segment].collect(joinPath(prefix, segment), paths)
+	}
+	return paths
+}
+
+// sortedSegments returns the segments of the children of t in sorted order.
+func (t *pathTrie) sortedSegments() []string {
+	segments := make([]string, 0, len(t.children))
+	for segment := range t.children {
+		segments = append(segments, segment)
+	}
+	slices.Sort(segments)
+	return segments
+}
+
+// elements returns the trie to apply to every element of a slice or array, where an optional leading wildcard
+// segment selects every element and is stripped. It reports whether a path selects the elements as a whole.
+func (t *pathTrie) elements() (*pathTrie, bool) {
+	wildcard := t.child(wildcardSegment)
+	if wildcard == nil {
+		return t, false
+	}
+	if wildcard.terminal {
+		return nil, true
+	}
+
+	rest := &pathTrie{terminal: t.terminal, children: make(map[string]*pathTrie, len(t.children)-1)}
+	for segment, node := range t.children {
+		if segment != wildcardSegment {
+			rest.children[segment] = node
+		}
+	}
+	return mergeTries(rest, wildcard), false
+}
+
+// mergeTries returns a trie holding the paths of both a and b, either of which may be nil. Neither input is modified,
+// and a new node is only allocated when both are present.
+func mergeTries(a, b *pathTrie) *pathTrie {
+	if a == nil {
+		return b
+	}
+	if b == nil || a == b {
+		return a
+	}
+
+	merged := &pathTrie{terminal: a.terminal || b.terminal, children: make(map[string]*pathTrie, len(a.children))}
+	for segment, node := range a.children {
+		merged.children[segment] = mergeTries(node, b.children[segment])
+	}
+	for segment, node := range b.children {
+		if _, ok := merged.children[segment]; !ok {
+			merged.children[segment] = node
+		}
+	}
+	return merged
+}
@@ -0,0 +1,261 @@
+package fieldmask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathTrie_HasPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "equal path",
+			paths:    []string{"a.b"},
+			input:    "a.b",
+			expected: true,
+		},
+		{
+			name:     "ancestor path",
+			paths:    []string{"a.b"},
+			input:    "a",
+			expected: true,
+		},
+		{
+			name:     "descendant path",
+			paths:    []string{"a.b"},
+			input:    "a.b.c",
+			expected: false,
+		},
+		{
+			name:     "shared string prefix is not a segment prefix",
+			paths:    []string{"ab.c"},
+			input:    "a",
+			expected: false,
+		},
+		{
+			name:     "wildcard in trie",
+			paths:    []string{"*.id"},
+			input:    "a.id",
+			expected: true,
+		},
+		{
+			name:     "wildcard in path",
+			paths:    []string{"a.id"},
+			input:    "*.id",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newPathTrie(tt.paths).hasPath(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Covers(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		input    string
+		strict   bool
+		expected bool
+	}{
+		{
+			name:     "equal path",
+			paths:    []string{"a.b"},
+			input:    "a.b",
+			expected: true,
+		},
+		{
+			name:     "equal path when strict",
+			paths:    []string{"a.b"},
+			input:    "a.b",
+			strict:   true,
+			expected: false,
+		},
+		{
+			name:     "ancestor in trie when strict",
+			paths:    []string{"a", "a.b"},
+			input:    "a.b",
+			strict:   true,
+			expected: true,
+		},
+		{
+			name:     "descendant in trie",
+			paths:    []string{"a.b"},
+			input:    "a",
+			expected: false,
+		},
+		{
+			name:     "wildcard in trie when strict",
+			paths:    []string{"a.*", "a.b"},
+			input:    "a.b",
+			strict:   true,
+			expected: true,
+		},
+		{
+			name:     "wildcard in path needs a wildcard",
+			paths:    []string{"a.b"},
+			input:    "a.*",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newPathTrie(tt.paths).covers(tt.input, tt.strict)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Intersect(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "equal path",
+			paths:    []string{"a.b"},
+			input:    "a.b",
+			expected: []string{"a.b"},
+		},
+		{
+			name:     "ancestor in trie",
+			paths:    []string{"a"},
+			input:    "a.b.c",
+			expected: []string{"a.b.c"},
+		},
+		{
+			name:     "descendants in trie",
+			paths:    []string{"a.c", "a.b.d"},
+			input:    "a",
+			expected: []string{"a.b.d", "a.c"},
+		},
+		{
+			name:     "wildcards on both sides",
+			paths:    []string{"profile.*"},
+			input:    "*.id",
+			expected: []string{"profile.id"},
+		},
+		{
+			name:  "disjoint paths",
+			paths: []string{"a.b"},
+			input: "a.c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newPathTrie(tt.paths).intersect(tt.input, "", nil)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPathTrie_Elements(t *testing.T) {
+	tests := []struct {
+		name            string
+		paths           []string
+		expected        []string
+		expectedKeepAll bool
+	}{
+		{
+			name:     "no wildcard",
+			paths:    []string{"id", "price"},
+			expected: []string{"id", "price"},
+		},
+		{
+			name:     "leading wildcard is stripped",
+			paths:    []string{"id", "*.price", "*.id.value"},
+			expected: []string{"id", "id.value", "price"},
+		},
+		{
+			name:            "bare wildcard keeps every element",
+			paths:           []string{"id", "*"},
+			expectedKeepAll: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, keepAll := newPathTrie(tt.paths).elements()
+			if keepAll != tt.expectedKeepAll {
+				t.Fatalf("expected keepAll %v, got %v", tt.expectedKeepAll, keepAll)
+			}
+			var got []string
+			if result != nil {
+				got = result.collect("", nil)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPathTrie_MergeTries(t *testing.T) {
+	a := newPathTrie([]string{"a.b", "c"})
+	b := newPathTrie([]string{"a.d", "e.f"})
+
+	merged := mergeTries(a, b)
+	if got, want := merged.collect("", nil), []string{"a.b", "a.d", "c", "e.f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeTries() = %v, want %v", got, want)
+	}
+	if got, want := a.collect("", nil), []string{"a.b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeTries() modified input: %v, want %v", got, want)
+	}
+	if mergeTries(a, nil) != a || mergeTries(nil, b) != b {
+		t.Error("mergeTries() with nil should return the other trie")
+	}
+}
+
+func TestFieldMask_Trie(t *testing.T) {
+	f := &FieldMask{Paths: []string{"a", "b.c"}}
+
+	first := f.trie()
+	if f.trie() != first {
+		t.Error("trie() should be cached while Paths is unchanged")
+	}
+
+	f.Paths = append(f.Paths, "d")
+	if f.trie() == first {
+		t.Error("trie() should be rebuilt after Paths grows")
+	}
+	if !f.HasPath("d") {
+		t.Error("HasPath() should see appended path")
+	}
+
+	f.Paths = []string{"x"}
+	if f.HasPath("a") || !f.HasPath("x") {
+		t.Error("HasPath() should see replaced paths")
+	}
+
+	f.Paths = append(f.Paths[:0], "y")
+	if f.HasPath("x") || !f.HasPath("y") {
+		t.Error("HasPath() should see paths edited in place")
+	}
+
+	f.Paths[0] = "z"
+	if f.HasPath("y") || !f.HasPath("z") {
+		t.Error("HasPath() should see elements replaced in place")
+	}
+
+	if !reflect.DeepEqual(f, &FieldMask{Paths: []string{"z"}}) {
+		t.Error("cached trie should not affect equality")
+	}
+}
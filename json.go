@@ -0,0 +1,107 @@
+package fieldmask
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FromJSON derives a FieldMask from the keys present in a JSON object, such as a sparse PATCH body. Every nested
+// object is expanded into the paths of its members, while arrays, scalars, nulls and empty objects are leaves.
+// Paths are sorted, and an empty object yields a nil FieldMask. Keys that cannot be told apart from other paths once
+// joined, such as empty keys, keys containing the path separator "." and the wildcard "*", are rejected with an error
+// wrapping ErrInvalidKey.
+func FromJSON(data []byte) (*FieldMask, error) {
+	return fromJSON(data, nil, nil)
+}
+
+// FromJSONFor derives a FieldMask from the keys present in a JSON object, resolving them against the struct type T.
+// Nested objects are expanded only when they correspond to a struct or a map keyed by strings, so fields holding
+// arbitrary JSON are kept as leaves. Returns an error if a key does not match any field of T.
+func FromJSONFor[T any](data []byte) (*FieldMask, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, ErrNoStruct
+	}
+
+	td, err := getTypeDescriptor(t, defaultResolver)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromJSON(data, td, t)
+}
+
+// fromJSON decodes data and collects the paths of its leaves, resolved against td and t when they are not nil.
+func fromJSON(data []byte, td *typeDescriptor, t reflect.Type) (*FieldMask, error) {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	obj, ok := document.(map[string]any)
+	if !ok {
+		return nil, ErrNoObject
+	}
+	if len(obj) == 0 {
+		return nil, nil
+	}
+
+	paths, err := jsonPaths(nil, "", obj, td, t)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(paths)
+	return New(paths...), nil
+}
+
+// jsonPaths appends the paths of the leaves of value, located at path, to paths. Non-empty objects are expanded into
+// their members, and any other value is a leaf. When t is not nil, members are resolved against the struct descriptor
+// td or the keys of a map, and objects held by any other type are leaves.
+func jsonPaths(paths []string, path string, value any, td *typeDescriptor, t reflect.Type) ([]string, error) {
+	obj, ok := value.(map[string]any)
+	if !ok || len(obj) == 0 {
+		return append(paths, path), nil
+	}
+
+	if t != nil {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		keyed := t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+		if !keyed && (t.Kind() != reflect.Struct || td == nil) {
+			return append(paths, path), nil
+		}
+	}
+
+	for key, member := range obj {
+		memberPath := joinPath(path, key)
+		if key == "" || key == wildcardSegment || strings.Contains(key, pathSeparator) {
+			return nil, &errFieldProcessing{fieldName: memberPath, segment: key, err: ErrInvalidKey}
+		}
+		memberDescriptor, memberType := td, t
+		if t != nil {
+			if t.Kind() == reflect.Map {
+				memberType = t.Elem()
+			} else {
+				fd, ok := td.fieldByName(key)
+				if !ok {
+					return nil, &errFieldProcessing{fieldName: memberPath, segment: key, err: ErrUnknownField}
+				}
+				memberDescriptor, memberType = fd.child, fd.typ
+			}
+		}
+
+		var err error
+		if paths, err = jsonPaths(paths, memberPath, member, memberDescriptor, memberType); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,184 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      *fieldmask.FieldMask
+		wantError bool
+	}{
+		{
+			name:  "empty object",
+			input: `{}`,
+			want:  nil,
+		},
+		{
+			name:  "flat object",
+			input: `{"name": "John", "tags": ["a"], "extra": null}`,
+			want:  fieldmask.New("extra", "name", "tags"),
+		},
+		{
+			name:  "nested objects",
+			input: `{"profile": {"age": 30, "bio": "dev"}, "attrs": {"color": {"value": "red"}}, "empty": {}}`,
+			want:  fieldmask.New("attrs.color.value", "empty", "profile.age", "profile.bio"),
+		},
+		{
+			name:      "not an object",
+			input:     `["name"]`,
+			wantError: true,
+		},
+		{
+			name:      "invalid json",
+			input:     `{"name":`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.FromJSON([]byte(tt.input))
+
+			if tt.wantError {
+				if err == nil {
+					t.Error("FromJSON() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("FromJSON() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromJSON_InvalidKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantPaths []string
+	}{
+		{
+			name:      "key containing the path separator",
+			input:     `{"a.b": 1}`,
+			wantPaths: []string{"a.b"},
+		},
+		{
+			name:      "nested empty key",
+			input:     `{"a": {"": 1}}`,
+			wantPaths: []string{"a."},
+		},
+		{
+			name:      "wildcard key",
+			input:     `{"*": 1}`,
+			wantPaths: []string{"*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fieldmask.FromJSON([]byte(tt.input))
+			if !errors.Is(err, fieldmask.ErrInvalidKey) {
+				t.Fatalf("FromJSON() error = %v, want %v", err, fieldmask.ErrInvalidKey)
+			}
+			if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestFromJSONFor(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Attr struct {
+		Value string `json:"value"`
+		Unit  string `json:"unit"`
+	}
+
+	type User struct {
+		Name     string           `json:"name"`
+		Profile  *Profile         `json:"profile"`
+		Attrs    map[string]*Attr `json:"attrs"`
+		Metadata map[string]any   `json:"metadata"`
+		Extra    any              `json:"extra"`
+		Tags     []string         `json:"tags"`
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    *fieldmask.FieldMask
+		wantErr error
+	}{
+		{
+			name:  "struct and map members",
+			input: `{"name": "John", "profile": {"age": 30}, "attrs": {"color": {"unit": "rgb"}, "size": null}}`,
+			want:  fieldmask.New("attrs.color.unit", "attrs.size", "name", "profile.age"),
+		},
+		{
+			name:  "objects held by untyped fields are leaves",
+			input: `{"metadata": {"a": {"b": 1}}, "extra": {"c": 2}}`,
+			want:  fieldmask.New("extra", "metadata.a"),
+		},
+		{
+			name:    "unknown top-level field",
+			input:   `{"nmae": "John"}`,
+			wantErr: fieldmask.ErrUnknownField,
+		},
+		{
+			name:    "unknown nested field",
+			input:   `{"profile": {"agee": 30}}`,
+			wantErr: fieldmask.ErrUnknownField,
+		},
+		{
+			name:    "not an object",
+			input:   `"name"`,
+			wantErr: fieldmask.ErrNoObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.FromJSONFor[User]([]byte(tt.input))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FromJSONFor() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("FromJSONFor() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromJSONFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromJSONFor_NoStruct(t *testing.T) {
+	if _, err := fieldmask.FromJSONFor[string]([]byte(`{}`)); !errors.Is(err, fieldmask.ErrNoStruct) {
+		t.Errorf("FromJSONFor() error = %v, want %v", err, fieldmask.ErrNoStruct)
+	}
+}
@@ -0,0 +1,41 @@
+package fieldmask
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+	leafTypes sync.Map
+)
+
+// RegisterLeaf declares the struct type T as an opaque leaf, like types implementing json.Marshaler or
+// encoding.TextMarshaler, such as time.Time. Fields of a leaf type are masked as a whole and paths cannot reach below
+// them, so for a field created_at of that type, "created_at" is the only valid path. See Registration in the package
+// documentation for when to call it.
+func RegisterLeaf[T any]() {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	leafTypes.Store(t, struct{}{})
+	descriptorCache.Clear()
+}
+
+// isLeafType reports whether the struct type t is opaque, because it or a pointer to it implements json.Marshaler or
+// encoding.TextMarshaler, or because it was registered with RegisterLeaf.
+func isLeafType(t reflect.Type) bool {
+	if _, ok := leafTypes.Load(t); ok {
+		return true
+	}
+
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textMarshalerType)
+}
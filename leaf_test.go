@@ -0,0 +1,182 @@
+package fieldmask_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.g3deon.com/fieldmask"
+)
+
+// textID implements encoding.TextMarshaler. It is declared at package level since methods cannot be declared on types
+// local to a test function.
+type textID struct {
+	Value string
+}
+
+func (id *textID) MarshalText() ([]byte, error) {
+	return []byte(id.Value), nil
+}
+
+func TestLeafTypes_Validate(t *testing.T) {
+	type Money struct {
+		Units int64 `json:"units"`
+	}
+
+	type Order struct {
+		ID        textID      `json:"id"`
+		CreatedAt time.Time   `json:"created_at"`
+		History   []time.Time `json:"history"`
+		Total     Money       `json:"total"`
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		wantError bool
+	}{
+		{
+			name: "whole opaque fields",
+			mask: fieldmask.New("id", "created_at", "history"),
+		},
+		{
+			name:      "below json.Marshaler",
+			mask:      fieldmask.New("created_at.wall"),
+			wantError: true,
+		},
+		{
+			name:      "below encoding.TextMarshaler",
+			mask:      fieldmask.New("id.Value"),
+			wantError: true,
+		},
+		{
+			name:      "below slice of opaque elements",
+			mask:      fieldmask.New("history.*.wall"),
+			wantError: true,
+		},
+		{
+			name: "below regular struct",
+			mask: fieldmask.New("total.units"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fieldmask.ValidateFor[Order](tt.mask)
+
+			if !tt.wantError {
+				if err != nil {
+					t.Errorf("ValidateFor() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !fieldmask.IsUnexpectedKindError(err) {
+				t.Errorf("ValidateFor() error = %v, want unexpected kind error", err)
+			}
+		})
+	}
+}
+
+func TestLeafTypes_Diff(t *testing.T) {
+	type Order struct {
+		Note      string                `json:"note"`
+		CreatedAt time.Time             `json:"created_at"`
+		Events    map[string]time.Time  `json:"events"`
+		Deadlines map[string]*time.Time `json:"deadlines"`
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := created.Add(time.Hour)
+	now := time.Now()
+	stripped := now.Round(0)
+
+	tests := []struct {
+		name string
+		a    *Order
+		b    *Order
+		want []string
+	}{
+		{
+			name: "opaque field",
+			a:    &Order{Note: "a", CreatedAt: created},
+			b:    &Order{Note: "a", CreatedAt: later},
+			want: []string{"created_at"},
+		},
+		{
+			name: "opaque map values",
+			a:    &Order{Events: map[string]time.Time{"created": created, "paid": created}},
+			b:    &Order{Events: map[string]time.Time{"created": created, "paid": later}},
+			want: []string{"events.paid"},
+		},
+		{
+			name: "opaque map pointer values",
+			a:    &Order{Deadlines: map[string]*time.Time{"ship": &created}},
+			b:    &Order{Deadlines: map[string]*time.Time{"ship": &later}},
+			want: []string{"deadlines.ship"},
+		},
+		{
+			name: "equal instants with and without monotonic reading",
+			a: &Order{
+				CreatedAt: now,
+				Events:    map[string]time.Time{"created": now},
+				Deadlines: map[string]*time.Time{"ship": &now},
+			},
+			b: &Order{
+				CreatedAt: stripped,
+				Events:    map[string]time.Time{"created": stripped},
+				Deadlines: map[string]*time.Time{"ship": &stripped},
+			},
+			want: nil,
+		},
+		{
+			name: "equal instants in different locations",
+			a:    &Order{CreatedAt: created},
+			b:    &Order{CreatedAt: created.In(time.FixedZone("CET", 3600))},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := fieldmask.Diff(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Diff() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(mask.GetPaths(), tt.want) {
+				t.Errorf("Diff() = %v, want %v", mask.GetPaths(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterLeaf(t *testing.T) {
+	type Money struct {
+		Units int64 `json:"units"`
+		Nanos int32 `json:"nanos"`
+	}
+
+	type Invoice struct {
+		Total Money `json:"total"`
+		Paid  Money `json:"paid"`
+	}
+
+	if err := fieldmask.ValidateFor[Invoice](fieldmask.New("total.units")); err != nil {
+		t.Fatalf("ValidateFor() unexpected error before registration: %v", err)
+	}
+
+	fieldmask.RegisterLeaf[Money]()
+
+	if err := fieldmask.ValidateFor[Invoice](fieldmask.New("total.units")); !fieldmask.IsUnexpectedKindError(err) {
+		t.Errorf("ValidateFor() error = %v, want unexpected kind error", err)
+	}
+
+	invoice := &Invoice{Total: Money{Units: 10, Nanos: 5}, Paid: Money{Units: 3}}
+	if err := fieldmask.New("total.units").Apply(invoice); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if want := (&Invoice{Total: Money{Units: 10, Nanos: 5}}); !reflect.DeepEqual(invoice, want) {
+		t.Errorf("Apply() = %+v, want %+v", invoice, want)
+	}
+}
@@ -0,0 +1,267 @@
+package fieldmask
+
+import (
+	"reflect"
+)
+
+// Merge copies the fields specified in f.Paths from src into dst, leaving every other field of dst untouched.
+// Both arguments must be non-nil pointers to the same struct type. Nil intermediate pointers and maps in dst are
+// allocated as needed, and a path naming a whole field, including a nested struct, replaces it entirely. Fields tagged
+// `fieldmask:",immutable"` are never updated, and nested structs holding them are merged field by field.
+func (f *FieldMask) Merge(dst, src any) error {
+	return defaultResolver.Merge(f, dst, src)
+}
+
+// Merge copies the fields specified in f.Paths from src into dst. See FieldMask.Merge.
+func (r *Resolver) Merge(f *FieldMask, dst, src any) error {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	dv, td, err := r.structPointer(dst)
+	if err != nil {
+		return err
+	}
+
+	sv, _, err := r.structPointer(src)
+	if err != nil {
+		return err
+	}
+
+	if dv.Type() != sv.Type() {
+		return ErrTypeMismatch
+	}
+
+	return td.merge(dv, sv, f.trie(), nil)
+}
+
+// Project returns a newly allocated value of the same type as src holding only the fields specified in f.Paths.
+// Unlike Apply, src is never modified, so it is safe to project shared or cached values concurrently. Fields kept as a
+// whole are copied shallowly, like a struct assignment. Like Apply, required fields and fields excluded by a tag of "-"
+// are always copied, while immutable and output-only fields are copied like any other field when selected. Pointers
+// shared within src, including cycles, are shared the same way in the projection. An empty mask returns a shallow copy
+// of src.
+func (f *FieldMask) Project(src any) (any, error) {
+	return defaultResolver.Project(f, src)
+}
+
+// Project returns a newly allocated value of the same type as src holding only the fields specified in f.Paths.
+// See FieldMask.Project.
+func (r *Resolver) Project(f *FieldMask, src any) (any, error) {
+	sv, td, err := r.structPointer(src)
+	if err != nil {
+		return nil, err
+	}
+
+	dv := reflect.New(sv.Type().Elem())
+	if f.IsEmpty() {
+		dv.Elem().Set(sv.Elem())
+		return dv.Interface(), nil
+	}
+
+	if err := td.merge(dv, sv, f.trie(), map[visitKey]reflect.Value{}); err != nil {
+		return nil, err
+	}
+	return dv.Interface(), nil
+}
+
+// Project is the typed counterpart of FieldMask.Project.
+func Project[T any](f *FieldMask, src *T) (*T, error) {
+	projected, err := f.Project(src)
+	if err != nil {
+		return nil, err
+	}
+	return projected.(*T), nil
+}
+
+// merge copies the values selected by the path trie from src into dst, which must be settable and of the same type.
+// Pointers are dereferenced, allocating them in dst when src holds a value, and a nil pointer in src is merged as a
+// zero value. Slices and arrays merge every element, resizing dst slices to the length of src. String-keyed maps treat
+// the first path segment as a key, deleting keys from dst that are absent in src. Leaves, including maps with any other
+// key type, are copied as a whole.
+// When merging, projected is nil and immutable fields are skipped. When projecting into a new value, required fields
+// are copied along with the structs holding them, and so are fields excluded by a tag of "-", like apply keeps them.
+// The projected map records the pointers of src already followed along with their copies in dst, so shared pointers
+// and cycles are copied once.
+func (d *typeDescriptor) merge(dst, src reflect.Value, paths *pathTrie, projected map[visitKey]reflect.Value) error {
+	project := projected != nil
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			if dst.IsNil() {
+				return nil
+			}
+			src = reflect.New(src.Type().Elem())
+		}
+		if project {
+			key := visitKey{addr: src.Pointer(), typ: src.Type()}
+			if copied, ok := projected[key]; ok {
+				dst.Set(copied)
+				return nil
+			}
+			projected[key] = dst
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return d.merge(dst.Elem(), src.Elem(), paths, projected)
+	case reflect.Slice, reflect.Array:
+		paths, replace := paths.elements()
+		if replace {
+			dst.Set(src)
+			return nil
+		}
+		if dst.Kind() == reflect.Slice && dst.Len() != src.Len() {
+			resized := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+			reflect.Copy(resized, dst)
+			dst.Set(resized)
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := d.merge(dst.Index(i), src.Index(i), paths, projected); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if !isKeyedMap(dst.Type()) {
+			dst.Set(src)
+			return nil
+		}
+		return d.mergeMap(dst, src, paths, projected)
+	case reflect.Struct:
+		// Structs reached through the scalar descriptor are opaque map values, replaced as a whole.
+		if d == scalarDescriptor {
+			dst.Set(src)
+			return nil
+		}
+	default:
+		dst.Set(src)
+		return nil
+	}
+
+	// Apply never clears fields excluded by a tag of "-", so the projection keeps them too.
+	if project {
+		for _, desc := range d.excluded {
+			if srcField, ok := desc.field(src, false); ok {
+				if dstField, ok := desc.field(dst, true); ok && dstField.CanSet() {
+					dstField.Set(srcField)
+				}
+			}
+		}
+	}
+
+	wildcard := paths.child(wildcardSegment)
+	replaceAll := wildcard.isTerminal()
+	for tag, desc := range d.fields {
+		if desc.immutable && !project {
+			continue
+		}
+
+		// Fields promoted through a nil embedded pointer in src are merged as zero values, and the embedded pointer
+		// is only allocated in dst when src holds the field.
+		srcField, ok := desc.field(src, false)
+		dstField, dstOK := desc.field(dst, ok)
+		if !dstOK || !dstField.CanSet() {
+			continue
+		}
+		if !ok {
+			srcField = getZero(desc.typ)
+		}
+
+		// Paths reaching below a leaf field replace the leaf as a whole.
+		// Fields holding immutable fields are merged field by field instead.
+		sub := d.lookup(paths, tag)
+		switch {
+		case sub.isTerminal() || replaceAll || (desc.child == nil && sub != nil) || (project && desc.required):
+			if project || !desc.holdsImmutable() {
+				dstField.Set(srcField)
+				continue
+			}
+			sub = wholeTrie
+		case desc.child == nil:
+			continue
+		default:
+			sub = mergeTries(sub, wildcard)
+			if sub == nil {
+				if !project || !desc.holdsRequired() {
+					continue
+				}
+				sub = emptyTrie
+			}
+		}
+
+		if err := desc.child.merge(dstField, srcField, sub, projected); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeMap merges a map keyed by strings, where the first segment of each path names a key or is the wildcard segment
+// matching every key present in either map. Entries are replaced or merged in an addressable copy that is stored back,
+// and entries absent from src are deleted from dst when replaced as a whole.
+func (d *typeDescriptor) mergeMap(dst, src reflect.Value, paths *pathTrie, projected map[visitKey]reflect.Value) error {
+	wildcard := paths.child(wildcardSegment)
+	replaceAll := wildcard.isTerminal()
+
+	names := make(map[string]struct{}, len(paths.children))
+	for name := range paths.children {
+		names[name] = struct{}{}
+	}
+	if wildcard != nil {
+		for _, key := range src.MapKeys() {
+			names[key.String()] = struct{}{}
+		}
+		for _, key := range dst.MapKeys() {
+			names[key.String()] = struct{}{}
+		}
+	}
+	delete(names, wildcardSegment)
+
+	keyType, elemType := dst.Type().Key(), dst.Type().Elem()
+	for name := range names {
+		key := reflect.ValueOf(name).Convert(keyType)
+		srcElem := src.MapIndex(key)
+
+		sub := paths.child(name)
+		if sub.isTerminal() || replaceAll {
+			if !srcElem.IsValid() {
+				if !dst.IsNil() {
+					dst.SetMapIndex(key, reflect.Value{})
+				}
+				continue
+			}
+			setMapIndex(dst, key, srcElem)
+			continue
+		}
+
+		dstElem := dst.MapIndex(key)
+		if !srcElem.IsValid() && !dstElem.IsValid() {
+			continue
+		}
+
+		elem := reflect.New(elemType).Elem()
+		if dstElem.IsValid() {
+			elem.Set(dstElem)
+		}
+		if !srcElem.IsValid() {
+			srcElem = getZero(elemType)
+		}
+
+		if err := d.merge(elem, srcElem, mergeTries(sub, wildcard), projected); err != nil {
+			return err
+		}
+		setMapIndex(dst, key, elem)
+	}
+
+	return nil
+}
+
+// setMapIndex stores elem under key, allocating the map first when it is nil.
+func setMapIndex(m, key, elem reflect.Value) {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	m.SetMapIndex(key, elem)
+}
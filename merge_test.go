@@ -0,0 +1,361 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldMask_Merge(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type User struct {
+		Name    string                    `json:"name"`
+		Email   string                    `json:"email"`
+		Profile Profile                   `json:"profile"`
+		Extra   *Profile                  `json:"extra"`
+		Items   []Item                    `json:"items"`
+		Attrs   map[string]*Item          `json:"attrs"`
+		Labels  map[string]string         `json:"labels"`
+		Scores  map[string]map[int]string `json:"scores"`
+	}
+
+	type Other struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name    string
+		mask    *fieldmask.FieldMask
+		dst     any
+		src     any
+		want    any
+		wantErr error
+	}{
+		{
+			name: "empty mask leaves destination untouched",
+			mask: fieldmask.New(),
+			dst:  &User{Name: "old"},
+			src:  &User{Name: "new"},
+			want: &User{Name: "old"},
+		},
+		{
+			name: "copy top-level and nested fields",
+			mask: fieldmask.New("name", "profile.age"),
+			dst:  &User{Name: "old", Email: "old@example.com", Profile: Profile{Age: 1, Bio: "old"}},
+			src:  &User{Name: "new", Email: "new@example.com", Profile: Profile{Age: 2, Bio: "new"}},
+			want: &User{Name: "new", Email: "old@example.com", Profile: Profile{Age: 2, Bio: "old"}},
+		},
+		{
+			name: "whole sub-message is replaced",
+			mask: fieldmask.New("profile"),
+			dst:  &User{Profile: Profile{Age: 1, Bio: "old"}},
+			src:  &User{Profile: Profile{Age: 2}},
+			want: &User{Profile: Profile{Age: 2}},
+		},
+		{
+			name: "nil intermediate pointer is allocated",
+			mask: fieldmask.New("extra.bio"),
+			dst:  &User{Name: "old"},
+			src:  &User{Name: "new", Extra: &Profile{Age: 2, Bio: "new"}},
+			want: &User{Name: "old", Extra: &Profile{Bio: "new"}},
+		},
+		{
+			name: "nil source pointer clears nested field",
+			mask: fieldmask.New("extra.bio"),
+			dst:  &User{Extra: &Profile{Age: 1, Bio: "old"}},
+			src:  &User{},
+			want: &User{Extra: &Profile{Age: 1}},
+		},
+		{
+			name: "nil source and destination pointers stay nil",
+			mask: fieldmask.New("extra.bio"),
+			dst:  &User{},
+			src:  &User{},
+			want: &User{},
+		},
+		{
+			name: "merge every slice element",
+			mask: fieldmask.New("items.price"),
+			dst:  &User{Items: []Item{{ID: "a", Price: 1}}},
+			src:  &User{Items: []Item{{ID: "x", Price: 10}, {ID: "y", Price: 20}}},
+			want: &User{Items: []Item{{ID: "a", Price: 10}, {Price: 20}}},
+		},
+		{
+			name: "merge map entries",
+			mask: fieldmask.New("attrs.color.price", "attrs.size", "labels.env"),
+			dst: &User{
+				Attrs:  map[string]*Item{"color": {ID: "c", Price: 1}, "size": {ID: "s", Price: 2}},
+				Labels: nil,
+			},
+			src: &User{
+				Attrs:  map[string]*Item{"color": {ID: "x", Price: 10}},
+				Labels: map[string]string{"env": "prod", "team": "core"},
+			},
+			want: &User{
+				Attrs:  map[string]*Item{"color": {ID: "c", Price: 10}},
+				Labels: map[string]string{"env": "prod"},
+			},
+		},
+		{
+			name: "merge every map entry with wildcard",
+			mask: fieldmask.New("attrs.*.id"),
+			dst:  &User{Attrs: map[string]*Item{"color": {ID: "c", Price: 1}}},
+			src:  &User{Attrs: map[string]*Item{"size": {ID: "s", Price: 2}}},
+			want: &User{Attrs: map[string]*Item{"color": {Price: 1}, "size": {ID: "s"}}},
+		},
+		{
+			name: "map with non-string keys below a map key is replaced as a whole",
+			mask: fieldmask.New("scores.a.*"),
+			dst:  &User{Scores: map[string]map[int]string{"a": {1: "old", 2: "old"}, "b": {3: "keep"}}},
+			src:  &User{Scores: map[string]map[int]string{"a": {1: "new"}}},
+			want: &User{Scores: map[string]map[int]string{"a": {1: "new"}, "b": {3: "keep"}}},
+		},
+		{
+			name:    "nil destination",
+			mask:    fieldmask.New("name"),
+			dst:     nil,
+			src:     &User{},
+			wantErr: fieldmask.ErrNilInput,
+		},
+		{
+			name:    "non-struct source",
+			mask:    fieldmask.New("name"),
+			dst:     &User{},
+			src:     new(string),
+			wantErr: fieldmask.ErrNoStruct,
+		},
+		{
+			name:    "different types",
+			mask:    fieldmask.New("name"),
+			dst:     &User{},
+			src:     &Other{},
+			wantErr: fieldmask.ErrTypeMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mask.Merge(tt.dst, tt.src)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Merge() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Merge() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Merge_Embedded(t *testing.T) {
+	type Audit struct {
+		UpdatedBy string `json:"updated_by"`
+		Reason    string `json:"reason"`
+	}
+
+	type User struct {
+		*Audit
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		dst  *User
+		src  *User
+		want *User
+	}{
+		{
+			name: "nil embedded pointer in destination is allocated",
+			mask: fieldmask.New("updated_by"),
+			dst:  &User{Name: "old"},
+			src:  &User{Audit: &Audit{UpdatedBy: "admin", Reason: "fix"}, Name: "new"},
+			want: &User{Audit: &Audit{UpdatedBy: "admin"}, Name: "old"},
+		},
+		{
+			name: "nil embedded pointer in source clears the field",
+			mask: fieldmask.New("updated_by"),
+			dst:  &User{Audit: &Audit{UpdatedBy: "admin", Reason: "fix"}},
+			src:  &User{},
+			want: &User{Audit: &Audit{Reason: "fix"}},
+		},
+		{
+			name: "nil embedded pointers on both sides stay nil",
+			mask: fieldmask.New("updated_by"),
+			dst:  &User{},
+			src:  &User{},
+			want: &User{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mask.Merge(tt.dst, tt.src); err != nil {
+				t.Fatalf("Merge() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Project(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type User struct {
+		Name    string                    `json:"name"`
+		Email   string                    `json:"email"`
+		Profile *Profile                  `json:"profile"`
+		Items   []Item                    `json:"items"`
+		Attrs   map[string]*Item          `json:"attrs"`
+		Scores  map[string]map[int]string `json:"scores"`
+	}
+
+	newUser := func() *User {
+		return &User{
+			Name:    "John",
+			Email:   "john@example.com",
+			Profile: &Profile{Age: 30, Bio: "Developer"},
+			Items:   []Item{{ID: "a", Price: 1}, {ID: "b", Price: 2}},
+			Attrs:   map[string]*Item{"color": {ID: "c", Price: 3}, "size": {ID: "s", Price: 4}},
+			Scores:  map[string]map[int]string{"a": {1: "one"}, "b": {2: "two"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		src       *User
+		want      *User
+		wantError bool
+	}{
+		{
+			name: "empty mask copies everything",
+			mask: fieldmask.New(),
+			src:  newUser(),
+			want: newUser(),
+		},
+		{
+			name: "project nested fields",
+			mask: fieldmask.New("name", "profile.age", "items.id", "attrs.color.price"),
+			src:  newUser(),
+			want: &User{
+				Name:    "John",
+				Profile: &Profile{Age: 30},
+				Items:   []Item{{ID: "a"}, {ID: "b"}},
+				Attrs:   map[string]*Item{"color": {Price: 3}},
+			},
+		},
+		{
+			name: "project with wildcard",
+			mask: fieldmask.New("profile.*", "attrs.*.id"),
+			src:  newUser(),
+			want: &User{
+				Profile: &Profile{Age: 30, Bio: "Developer"},
+				Attrs:   map[string]*Item{"color": {ID: "c"}, "size": {ID: "s"}},
+			},
+		},
+		{
+			name: "project map with non-string keys below a map key as a whole",
+			mask: fieldmask.New("scores.a.*"),
+			src:  newUser(),
+			want: &User{
+				Scores: map[string]map[int]string{"a": {1: "one"}},
+			},
+		},
+		{
+			name:      "nil source",
+			mask:      fieldmask.New("name"),
+			src:       nil,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.Project(tt.mask, tt.src)
+
+			if tt.wantError {
+				if err == nil {
+					t.Error("Project() expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Project() unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Project() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.src, newUser()) {
+				t.Errorf("Project() modified source: %+v", tt.src)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Project_Concurrent(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type User struct {
+		Name    string   `json:"name"`
+		Profile *Profile `json:"profile"`
+	}
+
+	shared := &User{Name: "John", Profile: &Profile{Age: 30, Bio: "Developer"}}
+	mask := fieldmask.New("profile.age")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := fieldmask.Project(mask, shared)
+			if err != nil {
+				t.Errorf("Project() unexpected error: %v", err)
+				return
+			}
+			if got.Name != "" || got.Profile.Age != 30 || got.Profile.Bio != "" || got.Profile == shared.Profile {
+				t.Errorf("Project() = %+v", got)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,73 @@
+package fieldmask
+
+import (
+	"strings"
+	"unicode"
+)
+
+const pathListSeparator = ","
+
+// Parse builds a FieldMask from its comma-separated string form, as used by query parameters such as
+// "?fields=name,profile.age" and by the proto3 JSON mapping of google.protobuf.FieldMask. Whitespace around paths and
+// segments is trimmed. Empty paths, empty segments and whitespace inside a segment are reported as syntax errors
+// carrying the byte offset of the problem, see IsSyntaxError. An empty or whitespace-only string returns a nil mask.
+func Parse(s string) (*FieldMask, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var paths []string
+	offset := 0
+	for raw := range strings.SplitSeq(s, pathListSeparator) {
+		path, err := parsePath(raw, offset)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		offset += len(raw) + len(pathListSeparator)
+	}
+
+	return New(paths...), nil
+}
+
+// Format returns the canonical comma-separated form of the FieldMask, the inverse of Parse. Unlike String, which is
+// meant for debugging, the output is stable across semantically identical masks and can be sent over the wire.
+// An empty mask formats as the empty string.
+func (f *FieldMask) Format() string {
+	if f.IsEmpty() {
+		return ""
+	}
+
+	canonical := &FieldMask{Paths: f.GetPaths()}
+	canonical.Canonicalize()
+	return strings.Join(canonical.Paths, pathListSeparator)
+}
+
+// parsePath trims and checks the segments of a single path found at offset in the parsed input.
+func parsePath(raw string, offset int) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", &errSyntax{offset: offset + leadingSpace(raw), msg: "empty path"}
+	}
+
+	segments := strings.Split(raw, pathSeparator)
+	for i, segment := range segments {
+		start := offset + leadingSpace(segment)
+		offset += len(segment) + len(pathSeparator)
+
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			return "", &errSyntax{offset: start, msg: "empty path segment"}
+		}
+		if j := strings.IndexFunc(segment, unicode.IsSpace); j != -1 {
+			return "", &errSyntax{offset: start + j, msg: "unexpected whitespace in path segment"}
+		}
+		segments[i] = segment
+	}
+
+	return strings.Join(segments, pathSeparator), nil
+}
+
+// leadingSpace returns the length in bytes of the whitespace at the start of s.
+func leadingSpace(s string) int {
+	return len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+}
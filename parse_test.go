@@ -0,0 +1,147 @@
+package fieldmask_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       []string
+		wantOffset int
+		wantError  bool
+	}{
+		{
+			name:  "empty string",
+			input: "",
+		},
+		{
+			name:  "whitespace only",
+			input: "  ",
+		},
+		{
+			name:  "single path",
+			input: "name",
+			want:  []string{"name"},
+		},
+		{
+			name:  "multiple paths",
+			input: "name,profile.age,items.*.id",
+			want:  []string{"name", "profile.age", "items.*.id"},
+		},
+		{
+			name:  "whitespace around paths and segments",
+			input: " name , profile . age ",
+			want:  []string{"name", "profile.age"},
+		},
+		{
+			name:  "duplicate paths",
+			input: "name,profile.age,name",
+			want:  []string{"name", "profile.age"},
+		},
+		{
+			name:       "empty path",
+			input:      "name,,age",
+			wantOffset: 5,
+			wantError:  true,
+		},
+		{
+			name:       "trailing separator",
+			input:      "name, ",
+			wantOffset: 6,
+			wantError:  true,
+		},
+		{
+			name:       "empty segment",
+			input:      "name,profile..age",
+			wantOffset: 13,
+			wantError:  true,
+		},
+		{
+			name:       "leading dot",
+			input:      ".name",
+			wantOffset: 0,
+			wantError:  true,
+		},
+		{
+			name:       "whitespace inside segment",
+			input:      "name, profile.first name",
+			wantOffset: 19,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.Parse(tt.input)
+
+			if tt.wantError {
+				if !fieldmask.IsSyntaxError(err) {
+					t.Fatalf("Parse() error = %v, want syntax error", err)
+				}
+				if offset, _ := fieldmask.SyntaxErrorOffset(err); offset != tt.wantOffset {
+					t.Errorf("SyntaxErrorOffset() = %d, want %d", offset, tt.wantOffset)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.GetPaths(), tt.want) {
+				t.Errorf("Parse() = %v, want %v", got.GetPaths(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Format(t *testing.T) {
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want string
+	}{
+		{
+			name: "nil mask",
+			mask: nil,
+			want: "",
+		},
+		{
+			name: "empty mask",
+			mask: &fieldmask.FieldMask{},
+			want: "",
+		},
+		{
+			name: "canonical order",
+			mask: fieldmask.New("profile.age", "name"),
+			want: "name,profile.age",
+		},
+		{
+			name: "redundant paths",
+			mask: fieldmask.New("profile", "profile.age", " name "),
+			want: "name,profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.Format()
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+
+			parsed, err := fieldmask.Parse(got)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if !parsed.Equal(tt.mask) {
+				t.Errorf("Parse(Format()) = %v, want %v", parsed, tt.mask)
+			}
+		})
+	}
+}
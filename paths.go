@@ -4,7 +4,23 @@ import (
 	"strings"
 )
 
-const pathSeparator = "."
+const (
+	pathSeparator   = "."
+	wildcardSegment = "*"
+)
+
+// trimPaths returns a copy of paths with whitespace trimmed around every segment.
+func trimPaths(paths []string) []string {
+	result := make([]string, len(paths))
+	for i, path := range paths {
+		segments := strings.Split(path, pathSeparator)
+		for j, segment := range segments {
+			segments[j] = strings.TrimSpace(segment)
+		}
+		result[i] = strings.Join(segments, pathSeparator)
+	}
+	return result
+}
 
 // removeEmptyPaths filters out empty or whitespace-only strings from the provided slice of paths.
 func removeEmptyPaths(paths []string) []string {
@@ -29,3 +45,24 @@ func removeDuplicatePaths(paths []string) []string {
 	}
 	return result
 }
+
+// removeRedundantPaths removes paths already covered by another path in the slice, such as "a.b" when "a" or "a.*" is
+// present. The input must not contain duplicates.
+func removeRedundantPaths(paths []string) []string {
+	index := newPathTrie(paths)
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !index.covers(path, true) {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// joinPath appends a segment to a path, returning the segment alone when the path is empty.
+func joinPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + pathSeparator + segment
+}
@@ -80,3 +80,79 @@ func Test_removeDuplicatePaths(t *testing.T) {
 		})
 	}
 }
+
+func Test_removeRedundantPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "no redundant paths",
+			input:    []string{"a", "b.c"},
+			expected: []string{"a", "b.c"},
+		},
+		{
+			name:     "descendant of present ancestor",
+			input:    []string{"a.b", "a", "a.c.d"},
+			expected: []string{"a"},
+		},
+		{
+			name:     "descendant of wildcard",
+			input:    []string{"a.b", "a.*", "*.c"},
+			expected: []string{"a.*", "*.c"},
+		},
+		{
+			name:     "empty input slice",
+			input:    []string{},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := removeRedundantPaths(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_trimPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "no whitespace",
+			input:    []string{"a", "b.c"},
+			expected: []string{"a", "b.c"},
+		},
+		{
+			name:     "whitespace around paths and segments",
+			input:    []string{" a ", "b . c", "\td.e\n"},
+			expected: []string{"a", "b.c", "d.e"},
+		},
+		{
+			name:     "whitespace-only path",
+			input:    []string{"  "},
+			expected: []string{""},
+		},
+		{
+			name:     "empty input slice",
+			input:    []string{},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := trimPaths(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package fieldmask
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+type (
+	// Plan is a FieldMask compiled against the struct type T. Paths are resolved and validated once by Compile, so
+	// applying a plan only visits the fields it has to zero or descend into, without building path maps per call.
+	// A Plan is immutable and safe for concurrent use.
+	Plan[T any] struct {
+		root *planNode
+	}
+
+	// planNode describes what to do with a value after pointers, slices and arrays around it are unwrapped. For a
+	// struct it lists the fields to zero and the fields to descend into. For a map keyed by strings it lists the
+	// nodes of the named keys, where a nil node keeps the entry as a whole, and the node of the remaining keys,
+	// along with a pool of mapEntry values to iterate over the map with. A nil planNode keeps the value as a whole.
+	planNode struct {
+		zero   []*fieldDescriptor
+		fields []planField
+
+		keyed       bool
+		keys        map[string]*planNode
+		hasWildcard bool
+		wildcard    *planNode
+		entries     *sync.Pool
+	}
+
+	// mapEntry holds addressable copies of a map key and element, reused across calls so that iterating over a map
+	// and processing its entries in place does not allocate.
+	mapEntry struct {
+		iter reflect.MapIter
+		key  reflect.Value
+		elem reflect.Value
+	}
+
+	planField struct {
+		desc *fieldDescriptor
+		node *planNode
+	}
+
+	// planWalker tracks the pointers followed while applying a plan. Plans are usually shallow, so visited pointers
+	// are kept in a small inline buffer searched linearly instead of a map, spilling over to a slice, which allocates,
+	// when it is full.
+	planWalker struct {
+		buf      [8]visitKey
+		n        int
+		overflow []visitKey
+	}
+)
+
+// Compile resolves the FieldMask against the struct type T into a Plan. It returns the validation error if any path
+// cannot be resolved. See FieldMask.Validate.
+func Compile[T any](f *FieldMask) (*Plan[T], error) {
+	return CompileWith[T](defaultResolver, f)
+}
+
+// CompileWith resolves the FieldMask against the struct type T into a Plan, naming fields with the resolver r.
+// See Compile.
+func CompileWith[T any](r *Resolver, f *FieldMask) (*Plan[T], error) {
+	t := reflect.TypeFor[T]()
+	if err := r.Validate(f, t); err != nil {
+		return nil, err
+	}
+
+	if f.IsEmpty() {
+		return &Plan[T]{}, nil
+	}
+
+	td, err := getTypeDescriptor(t, r)
+	if err != nil {
+		return nil, err
+	}
+
+	root := compileNode(td, t, f.trie(), map[*typeDescriptor]*planNode{})
+	if root == nil {
+		root = &planNode{}
+	}
+	return &Plan[T]{root: root}, nil
+}
+
+// Apply zeros all fields of v except those selected by the compiled mask, like FieldMask.Apply. A plan compiled from
+// an empty mask leaves v untouched.
+func (p *Plan[T]) Apply(v *T) error {
+	if v == nil {
+		return ErrNilInput
+	}
+	if p.root == nil {
+		return nil
+	}
+
+	var w planWalker
+	p.root.apply(reflect.ValueOf(v), &w)
+	return nil
+}
+
+// compileNode compiles the path trie against the type t described by d, following the same rules as apply.
+// It returns nil when the value is kept as a whole. Structs compiled with the empty trie to keep their required
+// fields are recorded in retained, so self-referential types compile to a cyclic plan instead of recursing forever.
+func compileNode(d *typeDescriptor, t reflect.Type, paths *pathTrie, retained map[*typeDescriptor]*planNode) *planNode {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return compileNode(d, t.Elem(), paths, retained)
+	case reflect.Slice, reflect.Array:
+		paths, keepAll := paths.elements()
+		if keepAll {
+			return nil
+		}
+		return compileNode(d, t.Elem(), paths, retained)
+	case reflect.Map:
+		if !isKeyedMap(t) {
+			return nil
+		}
+		return compileMapNode(d, t, paths, retained)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	node := &planNode{}
+	if paths == emptyTrie {
+		if cached, ok := retained[d]; ok {
+			return cached
+		}
+		retained[d] = node
+	}
+
+	wildcard := paths.child(wildcardSegment)
+	if wildcard.isTerminal() {
+		return nil
+	}
+
+	for tag, desc := range d.fields {
+		sub := d.lookup(paths, tag)
+		if sub.isTerminal() || desc.required {
+			continue
+		}
+
+		if desc.child == nil {
+			// Paths reaching below a leaf field keep the leaf as a whole.
+			if sub == nil {
+				node.zero = append(node.zero, desc)
+			}
+			continue
+		}
+
+		sub = mergeTries(sub, wildcard)
+		if sub == nil {
+			if !desc.holdsRequired() {
+				node.zero = append(node.zero, desc)
+				continue
+			}
+			sub = emptyTrie
+		}
+
+		if child := compileNode(desc.child, desc.typ, sub, retained); child != nil {
+			node.fields = append(node.fields, planField{desc: desc, node: child})
+		}
+	}
+
+	return node
+}
+
+// compileMapNode compiles the path trie against a map type keyed by strings, where the first segment of each path
+// names a key or is the wildcard segment matching every key.
+func compileMapNode(d *typeDescriptor, t reflect.Type, paths *pathTrie, retained map[*typeDescriptor]*planNode) *planNode {
+	wildcard := paths.child(wildcardSegment)
+	if wildcard.isTerminal() {
+		return nil
+	}
+
+	node := &planNode{keyed: true, keys: make(map[string]*planNode, len(paths.children))}
+	node.entries = &sync.Pool{New: func() any {
+		return &mapEntry{key: reflect.New(t.Key()).Elem(), elem: reflect.New(t.Elem()).Elem()}
+	}}
+	for name, sub := range paths.children {
+		if name == wildcardSegment {
+			continue
+		}
+		if sub.terminal {
+			node.keys[name] = nil
+			continue
+		}
+		node.keys[name] = compileNode(d, t.Elem(), mergeTries(sub, wildcard), retained)
+	}
+
+	if wildcard != nil {
+		node.hasWildcard = true
+		node.wildcard = compileNode(d, t.Elem(), wildcard, retained)
+	}
+	return node
+}
+
+// apply runs the node against value, dereferencing pointers and visiting every element of slices and arrays.
+func (n *planNode) apply(value reflect.Value, w *planWalker) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() || !w.visit(value) {
+			return
+		}
+		n.apply(value.Elem(), w)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			n.apply(value.Index(i), w)
+		}
+	case reflect.Map:
+		if n.keyed {
+			n.applyMap(value, w)
+		}
+	case reflect.Struct:
+		if !value.CanAddr() {
+			return
+		}
+		for _, desc := range n.zero {
+			if field, ok := desc.field(value, false); ok && field.CanSet() {
+				field.SetZero()
+			}
+		}
+		for _, planned := range n.fields {
+			if field, ok := planned.desc.field(value, false); ok {
+				planned.node.apply(field, w)
+			}
+		}
+	}
+}
+
+// applyMap runs the node against a map keyed by strings. Entries not named by any path are deleted, and entries with
+// a node are copied into an addressable value, processed and stored back.
+func (n *planNode) applyMap(value reflect.Value, w *planWalker) {
+	if value.IsNil() {
+		return
+	}
+
+	entry := n.entries.Get().(*mapEntry)
+	entry.iter.Reset(value)
+	for entry.iter.Next() {
+		entry.key.SetIterKey(&entry.iter)
+		node, named := n.keys[entry.key.String()]
+		if !named {
+			if !n.hasWildcard {
+				value.SetMapIndex(entry.key, reflect.Value{})
+				continue
+			}
+			node = n.wildcard
+		}
+		if node == nil {
+			continue
+		}
+
+		entry.elem.SetIterValue(&entry.iter)
+		node.apply(entry.elem, w)
+		value.SetMapIndex(entry.key, entry.elem)
+	}
+
+	// Drop the references to the map and its entries before returning the entry to the pool.
+	entry.iter.Reset(reflect.Value{})
+	entry.key.SetZero()
+	entry.elem.SetZero()
+	n.entries.Put(entry)
+}
+
+// visit records the pointer held by value and reports whether it was not visited before.
+func (w *planWalker) visit(value reflect.Value) bool {
+	key := visitKey{addr: value.Pointer(), typ: value.Type()}
+	if slices.Contains(w.buf[:w.n], key) || slices.Contains(w.overflow, key) {
+		return false
+	}
+
+	if w.n < len(w.buf) {
+		w.buf[w.n] = key
+		w.n++
+	} else {
+		w.overflow = append(w.overflow, key)
+	}
+	return true
+}
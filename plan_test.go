@@ -0,0 +1,238 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestCompile(t *testing.T) {
+	type Profile struct {
+		Age int `json:"age"`
+	}
+
+	type Item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type User struct {
+		Name    string           `json:"name"`
+		Profile *Profile         `json:"profile"`
+		Items   []Item           `json:"items"`
+		Attrs   map[string]*Item `json:"attrs"`
+		Friend  *User            `json:"friend"`
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		wantPaths []string
+		wantError error
+	}{
+		{
+			name: "empty mask",
+			mask: fieldmask.New(),
+		},
+		{
+			name: "valid paths",
+			mask: fieldmask.New("name", "profile.age", "items.*.id", "attrs.*.price", "friend.friend.name"),
+		},
+		{
+			name:      "unknown paths",
+			mask:      fieldmask.New("name", "profile.agee", "nmae"),
+			wantPaths: []string{"profile.agee", "nmae"},
+			wantError: fieldmask.ErrUnknownField,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := fieldmask.Compile[User](tt.mask)
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("Compile() error = %v, want %v", err, tt.wantError)
+				}
+				if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+					t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+				}
+				if plan != nil {
+					t.Errorf("Compile() plan = %v, want nil", plan)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Compile() unexpected error: %v", err)
+			}
+			if plan == nil {
+				t.Fatal("Compile() plan = nil")
+			}
+		})
+	}
+}
+
+func TestPlan_Apply(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type User struct {
+		Name    string            `json:"name"`
+		Email   string            `json:"email"`
+		Profile *Profile          `json:"profile"`
+		Items   []Item            `json:"items"`
+		Attrs   map[string]*Item  `json:"attrs"`
+		Labels  map[string]string `json:"labels"`
+		Friend  *User             `json:"friend"`
+	}
+
+	newUser := func() *User {
+		return &User{
+			Name:    "John",
+			Email:   "john@example.com",
+			Profile: &Profile{Age: 30, Bio: "Developer"},
+			Items:   []Item{{ID: "a", Price: 1}, {ID: "b", Price: 2}},
+			Attrs:   map[string]*Item{"color": {ID: "c", Price: 3}, "size": {ID: "s", Price: 4}},
+			Labels:  map[string]string{"env": "prod", "team": "core"},
+			Friend:  &User{Name: "Jane", Email: "jane@example.com", Profile: &Profile{Age: 28}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+	}{
+		{name: "empty mask"},
+		{name: "top-level fields", paths: []string{"name", "email"}},
+		{name: "nested pointer field", paths: []string{"profile.age"}},
+		{name: "whole nested field", paths: []string{"profile"}},
+		{name: "whole nested field next to its sub-path", paths: []string{"profile", "profile.age"}},
+		{name: "slice elements", paths: []string{"items.id"}},
+		{name: "slice elements with wildcard", paths: []string{"items.*.price"}},
+		{name: "map keys", paths: []string{"attrs.color", "labels.env"}},
+		{name: "map key with nested path", paths: []string{"attrs.size.price"}},
+		{name: "map wildcard", paths: []string{"attrs.*.id", "attrs.color.price"}},
+		{name: "struct wildcard", paths: []string{"*"}},
+		{name: "struct wildcard with sub-paths", paths: []string{"*.age", "name"}},
+		{name: "recursive type", paths: []string{"friend.name", "friend.profile.age"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask := fieldmask.New(tt.paths...)
+			want := newUser()
+			if err := mask.Apply(want); err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+
+			plan, err := fieldmask.Compile[User](mask)
+			if err != nil {
+				t.Fatalf("Compile() unexpected error: %v", err)
+			}
+
+			got := newUser()
+			if err := plan.Apply(got); err != nil {
+				t.Fatalf("Plan.Apply() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Plan.Apply() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestPlan_Apply_Cycle(t *testing.T) {
+	type User struct {
+		Name   string `json:"name"`
+		Email  string `json:"email"`
+		Friend *User  `json:"friend"`
+	}
+
+	mask := fieldmask.New("name", "friend.friend.friend.email")
+
+	want := &User{Name: "John", Email: "john@example.com"}
+	want.Friend = want
+	if err := mask.Apply(want); err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+
+	plan, err := fieldmask.Compile[User](mask)
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+
+	got := &User{Name: "John", Email: "john@example.com"}
+	got.Friend = got
+	if err := plan.Apply(got); err != nil {
+		t.Fatalf("Plan.Apply() unexpected error: %v", err)
+	}
+
+	if got.Name != want.Name || got.Email != want.Email || got.Friend != got {
+		t.Errorf("Plan.Apply() = {Name: %q, Email: %q}, want {Name: %q, Email: %q}", got.Name, got.Email, want.Name, want.Email)
+	}
+}
+
+func TestPlan_Apply_NilInput(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	plan, err := fieldmask.Compile[User](fieldmask.New("name"))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+
+	if err := plan.Apply(nil); !errors.Is(err, fieldmask.ErrNilInput) {
+		t.Errorf("Plan.Apply() error = %v, want %v", err, fieldmask.ErrNilInput)
+	}
+}
+
+func TestPlan_Apply_Allocs(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Item struct {
+		ID    string `json:"id"`
+		Price int    `json:"price"`
+	}
+
+	type User struct {
+		Name    string   `json:"name"`
+		Email   string   `json:"email"`
+		Profile *Profile `json:"profile"`
+		Items   []Item   `json:"items"`
+		Friend  *User    `json:"friend"`
+	}
+
+	plan, err := fieldmask.Compile[User](fieldmask.New("name", "profile.age", "items.id", "friend.name"))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+
+	user := &User{
+		Name:    "John",
+		Email:   "john@example.com",
+		Profile: &Profile{Age: 30, Bio: "Developer"},
+		Items:   []Item{{ID: "a", Price: 1}, {ID: "b", Price: 2}},
+		Friend:  &User{Name: "Jane", Email: "jane@example.com"},
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = plan.Apply(user)
+	})
+	if allocs != 0 {
+		t.Errorf("Plan.Apply() allocations = %v, want 0", allocs)
+	}
+}
@@ -0,0 +1,125 @@
+package fieldmask
+
+import (
+	"encoding/binary"
+	"strings"
+	"unicode/utf8"
+)
+
+// Protobuf wire types and the field number of the paths field of google.protobuf.FieldMask.
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+
+	protoPathsField = 1
+)
+
+// MarshalProto encodes the FieldMask as a google.protobuf.FieldMask message in the protobuf binary format. Protobuf
+// paths name proto fields, so lowerCamelCase segments such as "birthDate" are written in snake_case as "birth_date".
+// Other segments, such as snake_case names, Go field names like "Profile" or acronyms like "userID", are written as
+// they are, since converting them could not be undone. An empty mask encodes as an empty message.
+func (f *FieldMask) MarshalProto() []byte {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	var b []byte
+	for _, path := range f.Paths {
+		path = protoPath(path)
+		b = binary.AppendUvarint(b, protoPathsField<<3|protoWireBytes)
+		b = binary.AppendUvarint(b, uint64(len(path)))
+		b = append(b, path...)
+	}
+	return b
+}
+
+// UnmarshalProto decodes a google.protobuf.FieldMask message in the protobuf binary format, replacing the paths of the
+// FieldMask. Unknown fields are skipped. Paths are kept in snake_case like the proto field names they hold, and Wire
+// is set to WireProto3 so that encoding the mask as JSON produces the proto3 string with lowerCamelCase segments.
+// Malformed input returns a syntax error carrying the byte offset of the problem, see IsSyntaxError.
+func (f *FieldMask) UnmarshalProto(data []byte) error {
+	var paths []string
+	for offset := 0; offset < len(data); {
+		tag, n := binary.Uvarint(data[offset:])
+		if n <= 0 {
+			return &errSyntax{offset: offset, msg: "invalid protobuf field tag"}
+		}
+		field, wireType := tag>>3, tag&7
+
+		start := offset
+		offset += n
+		if field == protoPathsField && wireType != protoWireBytes {
+			return &errSyntax{offset: start, msg: "unexpected wire type for paths field"}
+		}
+
+		switch wireType {
+		case protoWireVarint:
+			_, n = binary.Uvarint(data[offset:])
+			if n <= 0 {
+				return &errSyntax{offset: offset, msg: "invalid protobuf varint"}
+			}
+			offset += n
+		case protoWireFixed64, protoWireFixed32:
+			size := 8
+			if wireType == protoWireFixed32 {
+				size = 4
+			}
+			if len(data)-offset < size {
+				return &errSyntax{offset: offset, msg: "truncated protobuf fixed-size value"}
+			}
+			offset += size
+		case protoWireBytes:
+			length, n := binary.Uvarint(data[offset:])
+			if n <= 0 {
+				return &errSyntax{offset: offset, msg: "invalid protobuf length"}
+			}
+			offset += n
+			if length > uint64(len(data)-offset) {
+				return &errSyntax{offset: offset, msg: "truncated protobuf length-delimited value"}
+			}
+
+			value := data[offset : offset+int(length)]
+			if field == protoPathsField {
+				if !utf8.Valid(value) {
+					return &errSyntax{offset: offset, msg: "path is not valid UTF-8"}
+				}
+				paths = append(paths, string(value))
+			}
+			offset += int(length)
+		default:
+			return &errSyntax{offset: start, msg: "unsupported protobuf wire type"}
+		}
+	}
+
+	f.Paths, f.Wire = paths, WireProto3
+	return nil
+}
+
+// protoPath converts the lowerCamelCase segments of path to snake_case, see isLowerCamelCase. Other segments are kept.
+func protoPath(path string) string {
+	segments := strings.Split(path, pathSeparator)
+	for i, segment := range segments {
+		if isLowerCamelCase(segment) {
+			segments[i] = snakeCase(segment)
+		}
+	}
+	return strings.Join(segments, pathSeparator)
+}
+
+// isLowerCamelCase reports whether segment is a lowerCamelCase name whose snake_case form converts back to it: it
+// starts with a lowercase ASCII letter and holds neither underscores nor consecutive uppercase letters.
+func isLowerCamelCase(segment string) bool {
+	if segment == "" || segment[0] < 'a' || segment[0] > 'z' {
+		return false
+	}
+
+	for i := 1; i < len(segment); i++ {
+		upper := segment[i] >= 'A' && segment[i] <= 'Z'
+		if segment[i] == '_' || upper && segment[i-1] >= 'A' && segment[i-1] <= 'Z' {
+			return false
+		}
+	}
+	return lowerCamelCase(snakeCase(segment)) == segment
+}
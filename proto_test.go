@@ -0,0 +1,168 @@
+package fieldmask_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldMask_MarshalProto(t *testing.T) {
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want []byte
+	}{
+		{
+			name: "nil mask",
+			mask: nil,
+			want: nil,
+		},
+		{
+			name: "snake_case paths",
+			mask: fieldmask.New("name", "profile.birth_date"),
+			want: append([]byte{0x0a, 0x04, 'n', 'a', 'm', 'e', 0x0a, 0x12}, "profile.birth_date"...),
+		},
+		{
+			name: "lowerCamelCase paths",
+			mask: fieldmask.New("profile.birthDate"),
+			want: append([]byte{0x0a, 0x12}, "profile.birth_date"...),
+		},
+		{
+			name: "Go field names are kept",
+			mask: fieldmask.New("Profile.Age"),
+			want: append([]byte{0x0a, 0x0b}, "Profile.Age"...),
+		},
+		{
+			name: "acronyms are kept",
+			mask: fieldmask.New("userID", "user.apiKey"),
+			want: append(append([]byte{0x0a, 0x06}, "userID"...), append([]byte{0x0a, 0x0c}, "user.api_key"...)...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.MarshalProto()
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("MarshalProto() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_UnmarshalProto(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []byte
+		want       []string
+		wantOffset int
+		wantError  bool
+	}{
+		{
+			name:  "empty message",
+			input: nil,
+		},
+		{
+			name:  "paths",
+			input: append([]byte{0x0a, 0x04, 'n', 'a', 'm', 'e', 0x0a, 0x12}, "profile.birth_date"...),
+			want:  []string{"name", "profile.birth_date"},
+		},
+		{
+			name:  "unknown fields are skipped",
+			input: []byte{0x10, 0x96, 0x01, 0x1a, 0x01, 'x', 0x25, 0, 0, 0, 0, 0x0a, 0x02, 'i', 'd', 0x29, 0, 0, 0, 0, 0, 0, 0, 0},
+			want:  []string{"id"},
+		},
+		{
+			name:       "truncated path",
+			input:      []byte{0x0a, 0x04, 'n', 'a'},
+			wantOffset: 2,
+			wantError:  true,
+		},
+		{
+			name:       "truncated tag",
+			input:      []byte{0x0a, 0x01, 'a', 0x80},
+			wantOffset: 3,
+			wantError:  true,
+		},
+		{
+			name:       "wrong wire type for paths",
+			input:      []byte{0x08, 0x01},
+			wantOffset: 0,
+			wantError:  true,
+		},
+		{
+			name:       "group wire type",
+			input:      []byte{0x13},
+			wantOffset: 0,
+			wantError:  true,
+		},
+		{
+			name:       "invalid UTF-8",
+			input:      []byte{0x0a, 0x01, 0xff},
+			wantOffset: 2,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &fieldmask.FieldMask{Paths: []string{"stale"}}
+			err := got.UnmarshalProto(tt.input)
+
+			if tt.wantError {
+				if !fieldmask.IsSyntaxError(err) {
+					t.Fatalf("UnmarshalProto() error = %v, want syntax error", err)
+				}
+				if offset, _ := fieldmask.SyntaxErrorOffset(err); offset != tt.wantOffset {
+					t.Errorf("SyntaxErrorOffset() = %d, want %d", offset, tt.wantOffset)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UnmarshalProto() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.GetPaths(), tt.want) {
+				t.Errorf("UnmarshalProto() = %v, want %v", got.GetPaths(), tt.want)
+			}
+			if got.Wire != fieldmask.WireProto3 {
+				t.Errorf("UnmarshalProto() wire = %v, want %v", got.Wire, fieldmask.WireProto3)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Proto_RoundTrip(t *testing.T) {
+	var fromJSON fieldmask.FieldMask
+	if err := json.Unmarshal([]byte(`"displayName,profile.birthDate"`), &fromJSON); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	var fromProto fieldmask.FieldMask
+	if err := fromProto.UnmarshalProto(fromJSON.MarshalProto()); err != nil {
+		t.Fatalf("UnmarshalProto() unexpected error: %v", err)
+	}
+
+	got, err := json.Marshal(&fromProto)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+	if want := `"displayName,profile.birthDate"`; string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestFieldMask_Proto_RoundTrip_Unconverted(t *testing.T) {
+	paths := []string{"Profile.Age", "userID", "birth_date"}
+
+	var decoded fieldmask.FieldMask
+	if err := decoded.UnmarshalProto(fieldmask.New(paths...).MarshalProto()); err != nil {
+		t.Fatalf("UnmarshalProto() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Paths, paths) {
+		t.Errorf("UnmarshalProto() paths = %v, want %v", decoded.Paths, paths)
+	}
+}
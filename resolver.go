@@ -0,0 +1,195 @@
+package fieldmask
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Naming selects how a field without a name in any of the configured struct tags is named in paths.
+type Naming int
+
+const (
+	// NamingGoName names fields by their Go name, such as "CreatedAt". It is the default, like in encoding/json.
+	NamingGoName Naming = iota
+	// NamingSnakeCase names fields in snake_case, such as "created_at" for CreatedAt and "user_id" for UserID.
+	NamingSnakeCase
+	// NamingLowerCamel names fields in lowerCamelCase, such as "createdAt" for CreatedAt and "userID" for UserID.
+	NamingLowerCamel
+)
+
+var defaultResolver = NewResolver(Options{})
+
+type (
+	// Options configures how a Resolver names struct fields in paths.
+	Options struct {
+		// TagKeys lists the struct tag keys consulted in order for a field name, such as "db" or "bson". The first
+		// tag holding a name, or "-" to exclude the field, wins. A nil list consults the json tag, while an empty
+		// non-nil list ignores tags altogether.
+		TagKeys []string
+		// Naming names the fields left without a name by the tags.
+		Naming Naming
+		// CaseInsensitive matches path segments against field names under Unicode case folding, so "Profile.AGE"
+		// selects the field named "profile.age". Exact matches take precedence.
+		CaseInsensitive bool
+	}
+
+	// Resolver resolves paths against struct types using a set of Options. The FieldMask methods that take a struct
+	// use a default Resolver reading json tags. Type descriptors are cached per type and resolver options, so a
+	// Resolver is cheap to create and safe for concurrent use.
+	Resolver struct {
+		opts Options
+		key  string
+	}
+)
+
+// NewResolver returns a Resolver naming fields according to opts.
+func NewResolver(opts Options) *Resolver {
+	if opts.TagKeys == nil {
+		opts.TagKeys = []string{"json"}
+	} else {
+		opts.TagKeys = append([]string{}, opts.TagKeys...)
+	}
+
+	key := strings.Join(opts.TagKeys, tagSeparator) + "|" + strconv.Itoa(int(opts.Naming)) + "|" +
+		strconv.FormatBool(opts.CaseInsensitive)
+	return &Resolver{opts: opts, key: key}
+}
+
+// Apply zeros all struct fields of i except those specified in f.Paths. See FieldMask.Apply.
+func (r *Resolver) Apply(f *FieldMask, i any) error {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	v, td, err := r.structPointer(i)
+	if err != nil {
+		return err
+	}
+
+	return td.apply(v, f.trie(), make(map[visitKey]bool))
+}
+
+// ApplyStrict behaves like Apply but first validates every path in f.Paths against the type of i.
+// See FieldMask.ApplyStrict.
+func (r *Resolver) ApplyStrict(f *FieldMask, i any) error {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	v, td, err := r.structPointer(i)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Validate(f, v.Type()); err != nil {
+		return err
+	}
+
+	return td.apply(v, f.trie(), make(map[visitKey]bool))
+}
+
+// Prune zeros only the struct fields of i specified in f.Paths. See FieldMask.Prune.
+func (r *Resolver) Prune(f *FieldMask, i any) error {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	v, td, err := r.structPointer(i)
+	if err != nil {
+		return err
+	}
+
+	return td.prune(v, f.trie(), make(map[visitKey]bool))
+}
+
+// tagName returns the name given to field by the first configured tag holding one, and reports whether such a tag
+// excludes the field with "-". It returns an empty name when no tag names the field.
+func (r *Resolver) tagName(field reflect.StructField) (string, bool) {
+	for _, key := range r.opts.TagKeys {
+		tag := field.Tag.Get(key)
+		if tag == tagIgnore {
+			return "", true
+		}
+		if name := parseTagName(tag); name != "" {
+			return name, false
+		}
+	}
+	return "", false
+}
+
+// structPointer validates that i is a non-nil pointer to a struct and returns its value along with the type descriptor.
+func (r *Resolver) structPointer(i any) (reflect.Value, *typeDescriptor, error) {
+	if i == nil {
+		return reflect.Value{}, nil, ErrNilInput
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}, nil, ErrNilInput
+	}
+
+	if v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, ErrNoStruct
+	}
+
+	td, err := getTypeDescriptor(v.Type(), r)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+
+	return v, td, nil
+}
+
+// name returns the path name of a field with the given Go name.
+func (n Naming) name(goName string) string {
+	switch n {
+	case NamingSnakeCase:
+		words := goNameWords(goName)
+		for i, word := range words {
+			words[i] = strings.ToLower(word)
+		}
+		return strings.Join(words, "_")
+	case NamingLowerCamel:
+		words := goNameWords(goName)
+		if len(words) == 0 {
+			return goName
+		}
+		words[0] = strings.ToLower(words[0])
+		return strings.Join(words, "")
+	default:
+		return goName
+	}
+}
+
+// goNameWords splits a Go identifier into words at underscores and case changes, keeping acronyms together, so
+// "UserID" yields "User" and "ID", and "HTTPServer" yields "HTTP" and "Server".
+func goNameWords(s string) []string {
+	var words []string
+	start := 0
+	var prev rune
+	for i, r := range s {
+		if r == '_' {
+			if i > start {
+				words = append(words, s[start:i])
+			}
+			start, prev = i+1, r
+			continue
+		}
+
+		if i > start && unicode.IsUpper(r) {
+			next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):])
+			if !unicode.IsUpper(prev) || unicode.IsLower(next) {
+				words = append(words, s[start:i])
+				start = i
+			}
+		}
+		prev = r
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
@@ -0,0 +1,199 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestResolver_Validate(t *testing.T) {
+	type Profile struct {
+		BirthDate string `db:"birth_date"`
+		HomePage  string
+	}
+
+	type User struct {
+		UserID   string  `json:"id" db:"user_id"`
+		Name     string  `json:"name"`
+		Password string  `json:"-" db:"password"`
+		Secret   string  `db:"-" json:"secret"`
+		Profile  Profile `json:"profile"`
+		HTTPHost string
+	}
+
+	tests := []struct {
+		name      string
+		options   fieldmask.Options
+		mask      *fieldmask.FieldMask
+		wantPaths []string
+	}{
+		{
+			name:    "default options read json tags",
+			options: fieldmask.Options{},
+			mask:    fieldmask.New("id", "name", "profile.BirthDate", "HTTPHost", "password", "user_id"),
+			wantPaths: []string{
+				"password",
+				"user_id",
+			},
+		},
+		{
+			name:      "ordered tag keys",
+			options:   fieldmask.Options{TagKeys: []string{"db", "json"}},
+			mask:      fieldmask.New("user_id", "name", "password", "profile.birth_date", "secret", "id"),
+			wantPaths: []string{"secret", "id"},
+		},
+		{
+			name:      "go names only",
+			options:   fieldmask.Options{TagKeys: []string{}},
+			mask:      fieldmask.New("UserID", "Password", "Secret", "Profile.BirthDate", "name"),
+			wantPaths: []string{"name"},
+		},
+		{
+			name:    "snake_case fallback",
+			options: fieldmask.Options{Naming: fieldmask.NamingSnakeCase},
+			mask:    fieldmask.New("id", "profile.birth_date", "profile.home_page", "http_host"),
+		},
+		{
+			name:    "lowerCamel fallback",
+			options: fieldmask.Options{TagKeys: []string{}, Naming: fieldmask.NamingLowerCamel},
+			mask:    fieldmask.New("userID", "profile.birthDate", "profile.homePage", "httpHost"),
+		},
+		{
+			name:      "case-sensitive by default",
+			options:   fieldmask.Options{},
+			mask:      fieldmask.New("Name", "PROFILE.HomePage"),
+			wantPaths: []string{"Name", "PROFILE.HomePage"},
+		},
+		{
+			name:    "case-insensitive matching",
+			options: fieldmask.Options{CaseInsensitive: true},
+			mask:    fieldmask.New("Name", "PROFILE.homepage", "httphost"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := fieldmask.NewResolver(tt.options)
+			err := r.Validate(tt.mask, reflect.TypeOf(User{}))
+
+			if len(tt.wantPaths) == 0 {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, fieldmask.ErrUnknownField) {
+				t.Fatalf("Validate() error = %v, want %v", err, fieldmask.ErrUnknownField)
+			}
+			if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestResolver_Apply(t *testing.T) {
+	type Profile struct {
+		Age int    `bson:"age"`
+		Bio string `bson:"bio"`
+	}
+
+	type User struct {
+		Name    string   `bson:"name"`
+		Email   string   `bson:"email"`
+		Profile *Profile `bson:"profile"`
+	}
+
+	r := fieldmask.NewResolver(fieldmask.Options{TagKeys: []string{"bson"}, CaseInsensitive: true})
+
+	t.Run("apply", func(t *testing.T) {
+		user := &User{Name: "John", Email: "john@example.com", Profile: &Profile{Age: 30, Bio: "Developer"}}
+		if err := r.Apply(fieldmask.New("Name", "profile.AGE"), user); err != nil {
+			t.Fatalf("Apply() unexpected error: %v", err)
+		}
+
+		want := &User{Name: "John", Profile: &Profile{Age: 30}}
+		if !reflect.DeepEqual(user, want) {
+			t.Errorf("Apply() = %+v, want %+v", user, want)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		dst := &User{Name: "old", Email: "old@example.com"}
+		src := &User{Name: "new", Email: "new@example.com"}
+		if err := r.Merge(fieldmask.New("EMAIL"), dst, src); err != nil {
+			t.Fatalf("Merge() unexpected error: %v", err)
+		}
+
+		want := &User{Name: "old", Email: "new@example.com"}
+		if !reflect.DeepEqual(dst, want) {
+			t.Errorf("Merge() = %+v, want %+v", dst, want)
+		}
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		mask, err := r.Diff(&User{Name: "a", Profile: &Profile{Age: 1}}, &User{Name: "b", Profile: &Profile{Age: 2}})
+		if err != nil {
+			t.Fatalf("Diff() unexpected error: %v", err)
+		}
+
+		if want := []string{"name", "profile.age"}; !reflect.DeepEqual(mask.GetPaths(), want) {
+			t.Errorf("Diff() = %v, want %v", mask.GetPaths(), want)
+		}
+	})
+
+	t.Run("compile", func(t *testing.T) {
+		plan, err := fieldmask.CompileWith[User](r, fieldmask.New("email"))
+		if err != nil {
+			t.Fatalf("CompileWith() unexpected error: %v", err)
+		}
+
+		user := &User{Name: "John", Email: "john@example.com"}
+		if err := plan.Apply(user); err != nil {
+			t.Fatalf("Plan.Apply() unexpected error: %v", err)
+		}
+
+		if want := (&User{Email: "john@example.com"}); !reflect.DeepEqual(user, want) {
+			t.Errorf("Plan.Apply() = %+v, want %+v", user, want)
+		}
+	})
+
+	t.Run("exact match takes precedence", func(t *testing.T) {
+		type Record struct {
+			Lower string `bson:"id"`
+			Upper string `bson:"ID"`
+		}
+
+		record := &Record{Lower: "lower", Upper: "upper"}
+		if err := r.Apply(fieldmask.New("ID"), record); err != nil {
+			t.Fatalf("Apply() unexpected error: %v", err)
+		}
+
+		if want := (&Record{Upper: "upper"}); !reflect.DeepEqual(record, want) {
+			t.Errorf("Apply() = %+v, want %+v", record, want)
+		}
+
+		dst := &Record{Lower: "old", Upper: "old"}
+		if err := r.Merge(fieldmask.New("id"), dst, &Record{Lower: "new", Upper: "new"}); err != nil {
+			t.Fatalf("Merge() unexpected error: %v", err)
+		}
+
+		if want := (&Record{Lower: "new", Upper: "old"}); !reflect.DeepEqual(dst, want) {
+			t.Errorf("Merge() = %+v, want %+v", dst, want)
+		}
+	})
+
+	t.Run("default resolver ignores bson tags", func(t *testing.T) {
+		user := &User{Name: "John", Email: "john@example.com"}
+		if err := fieldmask.New("Name").Apply(user); err != nil {
+			t.Fatalf("Apply() unexpected error: %v", err)
+		}
+
+		if want := (&User{Name: "John"}); !reflect.DeepEqual(user, want) {
+			t.Errorf("Apply() = %+v, want %+v", user, want)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package fieldmask
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	selectorSeparator = "/"
+	selectorDelimiter = ",/.(){}"
+)
+
+// selectorGroups maps the characters opening a group in the selector syntax to the characters closing them.
+var selectorGroups = map[byte]byte{'(': ')', '{': '}'}
+
+// selectorParser is a recursive descent parser for the selector syntax, tracking its byte offset in the input.
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+// ParseSelector builds a FieldMask from the grouped selector syntax of partial responses, expanding it into dot paths.
+// Segments are separated by "/" or ".", and a parenthesized or braced group applies its comma-separated items to the
+// path before it, so "items(id,name),profile/age" and "items{id,name},profile.age" both expand to "items.id",
+// "items.name" and "profile.age". Groups can be nested. Whitespace is allowed around segments and delimiters.
+// Malformed input returns a syntax error carrying the byte offset of the problem, see IsSyntaxError.
+// An empty or whitespace-only string returns a nil mask.
+func ParseSelector(s string) (*FieldMask, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	p := &selectorParser{input: s}
+	paths, err := p.parseList("", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	return New(paths...), nil
+}
+
+// FormatSelector returns the canonical form of the FieldMask in the compact selector syntax accepted by
+// ParseSelector. Paths sharing a prefix are grouped with parentheses and single-child chains are joined with "/",
+// so "items.id", "items.name" and "profile.age" format as "items(id,name),profile/age".
+// An empty mask formats as the empty string.
+func (f *FieldMask) FormatSelector() string {
+	if f.IsEmpty() {
+		return ""
+	}
+
+	canonical := &FieldMask{Paths: f.GetPaths()}
+	canonical.Canonicalize()
+
+	var b strings.Builder
+	writeSelector(&b, newPathTrie(canonical.Paths))
+	return b.String()
+}
+
+// writeSelector writes the children of t in sorted order, collapsing chains of single children and grouping the
+// remaining children in parentheses.
+func writeSelector(b *strings.Builder, t *pathTrie) {
+	for i, segment := range t.sortedSegments() {
+		if i > 0 {
+			b.WriteString(pathListSeparator)
+		}
+		b.WriteString(segment)
+
+		node := t.children[segment]
+		for !node.terminal && len(node.children) == 1 {
+			for next, child := range node.children {
+				b.WriteString(selectorSeparator)
+				b.WriteString(next)
+				node = child
+			}
+		}
+
+		if len(node.children) > 0 {
+			b.WriteByte('(')
+			writeSelector(b, node)
+			b.WriteByte(')')
+		}
+	}
+}
+
+// parseList parses comma-separated items below prefix, appending their paths, until a character that cannot
+// continue the list is found.
+func (p *selectorParser) parseList(prefix string, paths []string) ([]string, error) {
+	for {
+		var err error
+		paths, err = p.parseItem(prefix, paths)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.pos < len(p.input) && p.input[p.pos] == pathListSeparator[0] {
+			p.pos++
+			continue
+		}
+		return paths, nil
+	}
+}
+
+// parseItem parses a path below prefix, optionally followed by a group, and appends the resulting paths.
+func (p *selectorParser) parseItem(prefix string, paths []string) ([]string, error) {
+	path := prefix
+	for {
+		segment, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		path = joinPath(path, segment)
+
+		p.skipSpace()
+		if p.pos < len(p.input) && (p.input[p.pos] == selectorSeparator[0] || p.input[p.pos] == pathSeparator[0]) {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	if p.pos == len(p.input) {
+		return append(paths, path), nil
+	}
+
+	closer, ok := selectorGroups[p.input[p.pos]]
+	if !ok {
+		return append(paths, path), nil
+	}
+
+	open := p.pos
+	p.pos++
+	paths, err := p.parseList(path, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		p.pos = open
+		return nil, p.errorf("unclosed %q", p.input[open])
+	}
+	if p.input[p.pos] != closer {
+		return nil, p.errorf("unexpected %q, expected %q", p.input[p.pos], closer)
+	}
+	p.pos++
+
+	return paths, nil
+}
+
+// parseSegment parses a single path segment, which must not be empty.
+func (p *selectorParser) parseSegment() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && !isSelectorSpace(p.input[p.pos]) &&
+		!strings.Contains(selectorDelimiter, p.input[p.pos:p.pos+1]) {
+		p.pos++
+	}
+
+	if p.pos == start {
+		if p.pos == len(p.input) {
+			return "", p.errorf("unexpected end of input, expected field name")
+		}
+		return "", p.errorf("unexpected %q, expected field name", p.input[p.pos])
+	}
+	return p.input[start:p.pos], nil
+}
+
+// skipSpace advances past whitespace.
+func (p *selectorParser) skipSpace() {
+	for p.pos < len(p.input) && isSelectorSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// errorf returns a syntax error at the current offset.
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return &errSyntax{offset: p.pos, msg: fmt.Sprintf(format, args...)}
+}
+
+// isSelectorSpace reports whether c is an ASCII whitespace character.
+func isSelectorSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,164 @@
+package fieldmask_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       []string
+		wantOffset int
+		wantError  bool
+	}{
+		{
+			name:  "empty string",
+			input: "",
+		},
+		{
+			name:  "plain paths",
+			input: "name,profile.age",
+			want:  []string{"name", "profile.age"},
+		},
+		{
+			name:  "partial response syntax",
+			input: "items(id,name),profile/age",
+			want:  []string{"items.id", "items.name", "profile.age"},
+		},
+		{
+			name:  "brace syntax",
+			input: "profile{age,bio}",
+			want:  []string{"profile.age", "profile.bio"},
+		},
+		{
+			name:  "nested groups",
+			input: "a/b(c,d(e,f/g)),h",
+			want:  []string{"a.b.c", "a.b.d.e", "a.b.d.f.g", "h"},
+		},
+		{
+			name:  "whitespace around tokens",
+			input: " items ( id , name ) , profile / age ",
+			want:  []string{"items.id", "items.name", "profile.age"},
+		},
+		{
+			name:  "wildcard",
+			input: "items(*)",
+			want:  []string{"items.*"},
+		},
+		{
+			name:       "empty group",
+			input:      "items()",
+			wantOffset: 6,
+			wantError:  true,
+		},
+		{
+			name:       "unclosed group",
+			input:      "name,items(id,name",
+			wantOffset: 10,
+			wantError:  true,
+		},
+		{
+			name:       "mismatched group",
+			input:      "items(id}",
+			wantOffset: 8,
+			wantError:  true,
+		},
+		{
+			name:       "unexpected closing",
+			input:      "items),name",
+			wantOffset: 5,
+			wantError:  true,
+		},
+		{
+			name:       "trailing separator",
+			input:      "profile/",
+			wantOffset: 8,
+			wantError:  true,
+		},
+		{
+			name:       "whitespace inside segment",
+			input:      "first name",
+			wantOffset: 6,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.ParseSelector(tt.input)
+
+			if tt.wantError {
+				if !fieldmask.IsSyntaxError(err) {
+					t.Fatalf("ParseSelector() error = %v, want syntax error", err)
+				}
+				if offset, _ := fieldmask.SyntaxErrorOffset(err); offset != tt.wantOffset {
+					t.Errorf("SyntaxErrorOffset() = %d, want %d (%v)", offset, tt.wantOffset, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseSelector() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.GetPaths(), tt.want) {
+				t.Errorf("ParseSelector() = %v, want %v", got.GetPaths(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_FormatSelector(t *testing.T) {
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want string
+	}{
+		{
+			name: "empty mask",
+			mask: nil,
+			want: "",
+		},
+		{
+			name: "top-level paths",
+			mask: fieldmask.New("name", "email"),
+			want: "email,name",
+		},
+		{
+			name: "grouped paths",
+			mask: fieldmask.New("items.id", "items.name", "profile.age"),
+			want: "items(id,name),profile/age",
+		},
+		{
+			name: "chains and nested groups",
+			mask: fieldmask.New("a.b.c", "a.b.d.e", "a.b.d.f.g", "h"),
+			want: "a/b(c,d(e,f/g)),h",
+		},
+		{
+			name: "redundant paths",
+			mask: fieldmask.New("profile", "profile.age"),
+			want: "profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.FormatSelector()
+			if got != tt.want {
+				t.Errorf("FormatSelector() = %q, want %q", got, tt.want)
+			}
+
+			parsed, err := fieldmask.ParseSelector(got)
+			if err != nil {
+				t.Fatalf("ParseSelector() unexpected error: %v", err)
+			}
+			if !parsed.Equal(tt.mask) {
+				t.Errorf("ParseSelector(FormatSelector()) = %v, want %v", parsed, tt.mask)
+			}
+		})
+	}
+}
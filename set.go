@@ -0,0 +1,78 @@
+package fieldmask
+
+// Union returns a normalized FieldMask with the paths selected by either f or other. Paths covered by an ancestor
+// are dropped, so "profile" and "profile.age" yield "profile". Returns nil when both masks are empty.
+func (f *FieldMask) Union(other *FieldMask) *FieldMask {
+	paths := append(f.GetPaths(), other.GetPaths()...)
+	return newReduced(paths)
+}
+
+// Intersect returns a normalized FieldMask with the paths selected by both f and other, following the prefix
+// semantics of HasPath, so "profile" and "profile.age" yield "profile.age". Wildcard segments match any segment and
+// yield the more specific one. Returns nil when nothing is selected by both masks.
+func (f *FieldMask) Intersect(other *FieldMask) *FieldMask {
+	if f.IsEmpty() || other.IsEmpty() {
+		return nil
+	}
+
+	var paths []string
+	index := other.trie()
+	for _, a := range f.Paths {
+		paths = index.intersect(a, "", paths)
+	}
+	return newReduced(paths)
+}
+
+// Subtract returns a normalized FieldMask with the paths of f that are not covered by any path of other, so
+// "profile.age" is removed by "profile" or "profile.*". A path of f that other covers only partially, such as
+// "profile" minus "profile.age", is kept as is, since the remaining fields cannot be listed without knowing the type.
+// Returns nil when no path remains.
+func (f *FieldMask) Subtract(other *FieldMask) *FieldMask {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	remaining := &FieldMask{Paths: f.GetPaths()}
+	remaining.RemovePaths(other.GetPaths()...)
+	return newReduced(remaining.Paths)
+}
+
+// newReduced creates a normalized FieldMask from paths, dropping paths covered by an ancestor. Returns nil when no
+// path remains.
+func newReduced(paths []string) *FieldMask {
+	fm := New(paths...)
+	if fm.IsEmpty() {
+		return nil
+	}
+
+	fm.Paths = removeRedundantPaths(fm.Paths)
+	return fm
+}
+
+// Covers reports whether every path of other is selected by f, that is, equal to or a descendant of a path of f.
+// Wildcard segments in f match any segment, while a wildcard segment in other is only covered by a wildcard.
+// An empty other is always covered.
+func (f *FieldMask) Covers(other *FieldMask) bool {
+	return len(f.UncoveredPaths(other)) == 0
+}
+
+// IsSubsetOf reports whether every path of f is selected by other. See Covers.
+func (f *FieldMask) IsSubsetOf(other *FieldMask) bool {
+	return other.Covers(f)
+}
+
+// UncoveredPaths returns the paths of other that are not selected by f, in order, for instance to report exactly
+// which requested fields are denied by an allowed mask. Returns nil when f covers other.
+func (f *FieldMask) UncoveredPaths(other *FieldMask) []string {
+	if other.IsEmpty() {
+		return nil
+	}
+
+	var uncovered []string
+	for _, p := range other.Paths {
+		if f.IsEmpty() || !f.trie().covers(p, false) {
+			uncovered = append(uncovered, p)
+		}
+	}
+	return uncovered
+}
@@ -0,0 +1,213 @@
+package fieldmask_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldMask_Union(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  *fieldmask.FieldMask
+		other *fieldmask.FieldMask
+		want  *fieldmask.FieldMask
+	}{
+		{
+			name:  "both empty",
+			mask:  nil,
+			other: fieldmask.New(),
+			want:  nil,
+		},
+		{
+			name:  "one empty",
+			mask:  fieldmask.New("name"),
+			other: nil,
+			want:  fieldmask.New("name"),
+		},
+		{
+			name:  "disjoint paths",
+			mask:  fieldmask.New("name", "email"),
+			other: fieldmask.New("email", "profile.age"),
+			want:  fieldmask.New("name", "email", "profile.age"),
+		},
+		{
+			name:  "ancestor covers descendant",
+			mask:  fieldmask.New("profile.age", "name"),
+			other: fieldmask.New("profile"),
+			want:  fieldmask.New("name", "profile"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.Union(tt.other)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Union() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Intersect(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  *fieldmask.FieldMask
+		other *fieldmask.FieldMask
+		want  *fieldmask.FieldMask
+	}{
+		{
+			name:  "one empty",
+			mask:  fieldmask.New("name"),
+			other: nil,
+			want:  nil,
+		},
+		{
+			name:  "common paths",
+			mask:  fieldmask.New("name", "email", "id"),
+			other: fieldmask.New("email", "name", "profile"),
+			want:  fieldmask.New("name", "email"),
+		},
+		{
+			name:  "ancestor and descendant",
+			mask:  fieldmask.New("profile"),
+			other: fieldmask.New("profile.age", "profile.bio.text"),
+			want:  fieldmask.New("profile.age", "profile.bio.text"),
+		},
+		{
+			name:  "wildcards",
+			mask:  fieldmask.New("*.id", "items.*"),
+			other: fieldmask.New("profile", "items.name"),
+			want:  fieldmask.New("profile.id", "items.name"),
+		},
+		{
+			name:  "disjoint",
+			mask:  fieldmask.New("name"),
+			other: fieldmask.New("email"),
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.Intersect(tt.other)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Intersect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Subtract(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  *fieldmask.FieldMask
+		other *fieldmask.FieldMask
+		want  *fieldmask.FieldMask
+	}{
+		{
+			name:  "empty mask",
+			mask:  nil,
+			other: fieldmask.New("name"),
+			want:  nil,
+		},
+		{
+			name:  "empty other",
+			mask:  fieldmask.New("name", "profile.age", "profile"),
+			other: nil,
+			want:  fieldmask.New("name", "profile"),
+		},
+		{
+			name:  "remove covered paths",
+			mask:  fieldmask.New("name", "profile.age", "id", "items.price"),
+			other: fieldmask.New("id", "profile", "items.*"),
+			want:  fieldmask.New("name"),
+		},
+		{
+			name:  "partially covered path is kept",
+			mask:  fieldmask.New("profile"),
+			other: fieldmask.New("profile.age"),
+			want:  fieldmask.New("profile"),
+		},
+		{
+			name:  "everything removed",
+			mask:  fieldmask.New("name"),
+			other: fieldmask.New("name"),
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mask.Subtract(tt.other)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subtract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_Covers(t *testing.T) {
+	tests := []struct {
+		name          string
+		mask          *fieldmask.FieldMask
+		other         *fieldmask.FieldMask
+		want          bool
+		wantUncovered []string
+	}{
+		{
+			name:  "empty other",
+			mask:  nil,
+			other: fieldmask.New(),
+			want:  true,
+		},
+		{
+			name:          "empty mask",
+			mask:          nil,
+			other:         fieldmask.New("name"),
+			want:          false,
+			wantUncovered: []string{"name"},
+		},
+		{
+			name:  "equal and descendant paths",
+			mask:  fieldmask.New("name", "profile"),
+			other: fieldmask.New("profile.age", "name"),
+			want:  true,
+		},
+		{
+			name:  "wildcard covers any segment",
+			mask:  fieldmask.New("*.id", "items.*"),
+			other: fieldmask.New("profile.id", "items.name.first"),
+			want:  true,
+		},
+		{
+			name:          "ancestor is not covered by descendant",
+			mask:          fieldmask.New("profile.age", "name"),
+			other:         fieldmask.New("profile", "name", "password_hash"),
+			want:          false,
+			wantUncovered: []string{"profile", "password_hash"},
+		},
+		{
+			name:          "wildcard in other needs a wildcard",
+			mask:          fieldmask.New("profile.id"),
+			other:         fieldmask.New("*.id"),
+			want:          false,
+			wantUncovered: []string{"*.id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mask.Covers(tt.other); got != tt.want {
+				t.Errorf("Covers() = %v, want %v", got, tt.want)
+			}
+			if got := tt.other.IsSubsetOf(tt.mask); got != tt.want {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.want)
+			}
+			if got := tt.mask.UncoveredPaths(tt.other); !reflect.DeepEqual(got, tt.wantUncovered) {
+				t.Errorf("UncoveredPaths() = %v, want %v", got, tt.wantUncovered)
+			}
+		})
+	}
+}
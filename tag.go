@@ -0,0 +1,55 @@
+package fieldmask
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	fieldmaskTagKey = "fieldmask"
+
+	tagOptionLeaf       = "leaf"
+	tagOptionRequired   = "required"
+	tagOptionImmutable  = "immutable"
+	tagOptionOutputOnly = "output_only"
+)
+
+// fieldOptions holds the options declared by the fieldmask struct tag of a field.
+type fieldOptions struct {
+	// leaf stops descent into a struct field, which is then masked as a whole.
+	leaf bool
+	// immutable prevents Merge from updating the field.
+	immutable bool
+	// behavior holds the field behaviors declared by the tag, such as Required, or registered with
+	// RegisterFieldBehavior.
+	behavior FieldBehavior
+}
+
+// parseFieldmaskTag parses the fieldmask struct tag of field, which holds an optional path alias followed by
+// comma-separated options, such as `fieldmask:"id,required,immutable"`. It reports whether the tag excludes the field
+// from masking with "-", and returns an invalid tag error for unknown options.
+func parseFieldmaskTag(field reflect.StructField) (string, fieldOptions, bool, error) {
+	var opts fieldOptions
+	tag := field.Tag.Get(fieldmaskTagKey)
+	if tag == tagIgnore {
+		return "", opts, true, nil
+	}
+
+	name, rest, _ := strings.Cut(tag, tagSeparator)
+	for option := range strings.SplitSeq(rest, tagSeparator) {
+		switch option {
+		case "":
+		case tagOptionLeaf:
+			opts.leaf = true
+		case tagOptionRequired:
+			opts.behavior |= Required
+		case tagOptionImmutable:
+			opts.immutable = true
+		case tagOptionOutputOnly:
+			opts.behavior |= OutputOnly
+		default:
+			return "", opts, false, &errInvalidTag{field: field.Name, option: option}
+		}
+	}
+	return name, opts, false, nil
+}
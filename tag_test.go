@@ -0,0 +1,316 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldmaskTag_Validate(t *testing.T) {
+	type Meta struct {
+		Version int `json:"version"`
+	}
+
+	type Address struct {
+		Street string `json:"street"`
+	}
+
+	type User struct {
+		Name     string   `json:"name" fieldmask:"display_name"`
+		Email    string   `json:"email" fieldmask:",immutable"`
+		Password string   `json:"password" fieldmask:"-"`
+		Meta     Meta     `json:"meta" fieldmask:",leaf"`
+		Address  *Address `json:"address"`
+		Parent   *User    `json:"parent"`
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		wantPaths []string
+	}{
+		{
+			name: "alias and regular names",
+			mask: fieldmask.New("display_name", "email", "meta", "address.street", "parent.parent.email"),
+		},
+		{
+			name:      "alias replaces the json name",
+			mask:      fieldmask.New("name"),
+			wantPaths: []string{"name"},
+		},
+		{
+			name:      "excluded field",
+			mask:      fieldmask.New("password"),
+			wantPaths: []string{"password"},
+		},
+		{
+			name:      "no descent below leaf",
+			mask:      fieldmask.New("meta.version"),
+			wantPaths: []string{"meta.version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fieldmask.ValidateFor[User](tt.mask)
+
+			if len(tt.wantPaths) == 0 {
+				if err != nil {
+					t.Errorf("ValidateFor() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestFieldmaskTag_Apply(t *testing.T) {
+	type Meta struct {
+		Version int    `json:"version"`
+		Owner   string `json:"owner"`
+	}
+
+	type Address struct {
+		ID     string `json:"id" fieldmask:",required"`
+		Street string `json:"street"`
+	}
+
+	type User struct {
+		ID       string   `json:"id" fieldmask:",required"`
+		Name     string   `json:"name" fieldmask:"display_name"`
+		Email    string   `json:"email"`
+		Password string   `json:"password" fieldmask:"-"`
+		Meta     Meta     `json:"meta" fieldmask:",leaf"`
+		Address  *Address `json:"address"`
+		Parent   *User    `json:"parent"`
+	}
+
+	newUser := func() *User {
+		return &User{
+			ID:       "u1",
+			Name:     "John",
+			Email:    "john@example.com",
+			Password: "secret",
+			Meta:     Meta{Version: 2, Owner: "admin"},
+			Address:  &Address{ID: "a1", Street: "Main"},
+			Parent:   &User{ID: "u0", Name: "Jane"},
+		}
+	}
+
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want *User
+	}{
+		{
+			name: "required and excluded fields are always kept",
+			mask: fieldmask.New("display_name"),
+			want: &User{
+				ID:       "u1",
+				Name:     "John",
+				Password: "secret",
+				Address:  &Address{ID: "a1"},
+				Parent:   &User{ID: "u0"},
+			},
+		},
+		{
+			name: "required fields inside unselected structs are kept",
+			mask: fieldmask.New("email", "parent.display_name"),
+			want: &User{
+				ID:       "u1",
+				Email:    "john@example.com",
+				Password: "secret",
+				Address:  &Address{ID: "a1"},
+				Parent:   &User{ID: "u0", Name: "Jane"},
+			},
+		},
+		{
+			name: "leaf struct is kept as a whole",
+			mask: fieldmask.New("meta"),
+			want: &User{
+				ID:       "u1",
+				Password: "secret",
+				Meta:     Meta{Version: 2, Owner: "admin"},
+				Address:  &Address{ID: "a1"},
+				Parent:   &User{ID: "u0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newUser()
+			if err := tt.mask.Apply(got); err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+
+			plan, err := fieldmask.Compile[User](tt.mask)
+			if err != nil {
+				t.Fatalf("Compile() unexpected error: %v", err)
+			}
+			got = newUser()
+			if err := plan.Apply(got); err != nil {
+				t.Fatalf("Plan.Apply() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan.Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldmaskTag_Prune(t *testing.T) {
+	type Address struct {
+		ID     string `json:"id" fieldmask:",required"`
+		Street string `json:"street"`
+	}
+
+	type User struct {
+		ID       string   `json:"id" fieldmask:",required"`
+		Name     string   `json:"name"`
+		Password string   `json:"password" fieldmask:"-"`
+		Address  *Address `json:"address"`
+	}
+
+	got := &User{ID: "u1", Name: "John", Password: "secret", Address: &Address{ID: "a1", Street: "Main"}}
+	if err := fieldmask.New("id", "password", "address").Prune(got); err != nil {
+		t.Fatalf("Prune() unexpected error: %v", err)
+	}
+
+	want := &User{ID: "u1", Name: "John", Password: "secret", Address: &Address{ID: "a1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Prune() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFieldmaskTag_Merge(t *testing.T) {
+	type Address struct {
+		ID     string `json:"id" fieldmask:",immutable"`
+		Street string `json:"street"`
+		City   string `json:"city"`
+	}
+
+	type User struct {
+		ID      string   `json:"id"`
+		Name    string   `json:"name" fieldmask:"display_name"`
+		Email   string   `json:"email" fieldmask:",immutable"`
+		Address *Address `json:"address"`
+	}
+
+	dst := &User{
+		ID:      "u1",
+		Name:    "John",
+		Email:   "john@example.com",
+		Address: &Address{ID: "a1", Street: "Main", City: "Springfield"},
+	}
+	src := &User{ID: "u2", Name: "Johnny", Email: "johnny@example.com", Address: &Address{ID: "a2", Street: "Elm"}}
+
+	if err := fieldmask.New("id", "display_name", "email", "address").Merge(dst, src); err != nil {
+		t.Fatalf("Merge() unexpected error: %v", err)
+	}
+
+	want := &User{ID: "u2", Name: "Johnny", Email: "john@example.com", Address: &Address{ID: "a1", Street: "Elm"}}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("Merge() = %+v, want %+v", dst, want)
+	}
+}
+
+func TestFieldmaskTag_Invalid(t *testing.T) {
+	type Unknown struct {
+		Name string `fieldmask:",readonly"`
+	}
+	type Identity struct {
+		Name string `fieldmask:",identity"`
+	}
+
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{
+			name:  "unknown option",
+			input: &Unknown{},
+		},
+		{
+			name:  "identity is spelled required",
+			input: &Identity{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fieldmask.New("Name").Apply(tt.input)
+			if !fieldmask.IsInvalidTagError(err) {
+				t.Errorf("Apply() error = %v, want invalid tag error", err)
+			}
+			if errors.Is(err, fieldmask.ErrUnknownField) {
+				t.Errorf("Apply() error = %v, want no unknown field error", err)
+			}
+		})
+	}
+}
+
+func TestFieldmaskTag_Project(t *testing.T) {
+	type Book struct {
+		ID     string `json:"id" fieldmask:",immutable"`
+		Title  string `json:"title"`
+		Author string `json:"author"`
+		Notes  string `json:"notes" fieldmask:"-"`
+		Cache  string `json:"-"`
+	}
+
+	newBook := func() *Book {
+		return &Book{ID: "b1", Title: "Go", Author: "Rob", Notes: "n", Cache: "c"}
+	}
+
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want *Book
+	}{
+		{
+			name: "selected immutable field is copied",
+			mask: fieldmask.New("title", "id"),
+			want: &Book{ID: "b1", Title: "Go", Notes: "n", Cache: "c"},
+		},
+		{
+			name: "wildcard copies immutable fields",
+			mask: fieldmask.New("*"),
+			want: &Book{ID: "b1", Title: "Go", Author: "Rob", Notes: "n", Cache: "c"},
+		},
+		{
+			name: "unselected immutable field is not copied",
+			mask: fieldmask.New("title"),
+			want: &Book{Title: "Go", Notes: "n", Cache: "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldmask.Project(tt.mask, newBook())
+			if err != nil {
+				t.Fatalf("Project() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Project() = %+v, want %+v", got, tt.want)
+			}
+
+			applied := newBook()
+			if err := tt.mask.Apply(applied); err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, applied) {
+				t.Errorf("Project() = %+v, want the result of Apply() %+v", got, applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package fieldmask
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Validate checks that every path in the FieldMask resolves against the struct type t, or a pointer to it.
+// It returns an error joining one field processing error per unresolvable path, recording the segment where
+// resolution failed and wrapping either ErrUnknownField or an unexpected kind error when a path reaches below a leaf.
+// Wildcard segments are valid wherever a struct, slice, array or map can be traversed.
+func (f *FieldMask) Validate(t reflect.Type) error {
+	return defaultResolver.Validate(f, t)
+}
+
+// Validate checks that every path in the FieldMask resolves against the struct type t, or a pointer to it.
+// See FieldMask.Validate.
+func (r *Resolver) Validate(f *FieldMask, t reflect.Type) error {
+	return r.validate(f, t, false)
+}
+
+// ValidateUpdate checks an update mask against the struct type t, or a pointer to it, like Validate, and also rejects
+// paths naming an output-only field or a field below one, wrapping ErrOutputOnly. Wildcard segments never name
+// output-only fields, since Merge leaves them untouched. See FieldMask.ValidateUpdate.
+func (r *Resolver) ValidateUpdate(f *FieldMask, t reflect.Type) error {
+	return r.validate(f, t, true)
+}
+
+// validate resolves every path of f against t, rejecting explicit output-only fields when update is set.
+func (r *Resolver) validate(f *FieldMask, t reflect.Type, update bool) error {
+	if t == nil {
+		return ErrNilInput
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ErrNoStruct
+	}
+
+	if f.IsEmpty() {
+		return nil
+	}
+
+	td, err := getTypeDescriptor(t, r)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range f.Paths {
+		if segment, err := resolvePath(td, t, strings.Split(p, pathSeparator), update); err != nil {
+			errs = append(errs, &errFieldProcessing{fieldName: p, segment: segment, err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateFor checks that every path in the FieldMask resolves against the struct type T. See FieldMask.Validate.
+func ValidateFor[T any](f *FieldMask) error {
+	return f.Validate(reflect.TypeFor[T]())
+}
+
+// resolvePath resolves path segments against the type t described by td. Pointers are dereferenced, slices and arrays
+// resolve the segments against their elements with an optional leading wildcard segment, and maps keyed by strings
+// accept any segment as a key. When update is set, segments naming output-only fields are rejected.
+// On failure it returns the offending segment and the cause.
+func resolvePath(td *typeDescriptor, t reflect.Type, segments []string, update bool) (string, error) {
+	for i := 0; i < len(segments); {
+		segment := segments[i]
+		switch t.Kind() {
+		case reflect.Ptr:
+			t = t.Elem()
+			continue
+		case reflect.Slice, reflect.Array:
+			t = t.Elem()
+			if segment == wildcardSegment {
+				i++
+			}
+			continue
+		case reflect.Map:
+			if t.Key().Kind() == reflect.String {
+				t = t.Elem()
+				i++
+				continue
+			}
+		case reflect.Struct:
+			if td == nil {
+				break
+			}
+			if segment == wildcardSegment {
+				return resolveWildcard(td, segments[i+1:])
+			}
+
+			fd, ok := td.fieldByName(segment)
+			if !ok {
+				return segment, ErrUnknownField
+			}
+			if update && fd.outputOnly {
+				return segment, ErrOutputOnly
+			}
+			td, t = fd.child, fd.typ
+			i++
+			continue
+		}
+
+		return segment, &errUnexpectedKind{kind: t.Kind()}
+	}
+
+	return "", nil
+}
+
+// resolveWildcard resolves the segments following a wildcard segment, which succeeds when they resolve against at
+// least one field of the struct described by td.
+func resolveWildcard(td *typeDescriptor, segments []string) (string, error) {
+	if len(segments) == 0 {
+		return "", nil
+	}
+
+	for _, fd := range td.fields {
+		if _, err := resolvePath(fd.child, fd.typ, segments, false); err == nil {
+			return "", nil
+		}
+	}
+
+	return segments[0], ErrUnknownField
+}
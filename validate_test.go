@@ -0,0 +1,129 @@
+package fieldmask_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldMask_Validate(t *testing.T) {
+	type Profile struct {
+		Age int    `json:"age"`
+		Bio string `json:"bio"`
+	}
+
+	type Item struct {
+		ID string `json:"id"`
+	}
+
+	type User struct {
+		Name    string            `json:"name"`
+		Profile *Profile          `json:"profile"`
+		Items   []Item            `json:"items"`
+		Attrs   map[string]*Item  `json:"attrs"`
+		Labels  map[string]string `json:"labels"`
+		Counts  map[int]Item      `json:"counts"`
+	}
+
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		input     reflect.Type
+		wantPaths []string
+		wantError error
+	}{
+		{
+			name:  "empty mask",
+			mask:  fieldmask.New(),
+			input: reflect.TypeOf(User{}),
+		},
+		{
+			name:  "valid paths",
+			mask:  fieldmask.New("name", "profile.age", "items.id", "items.*.id", "attrs.color.id", "labels.env"),
+			input: reflect.TypeOf(&User{}),
+		},
+		{
+			name:  "valid wildcard paths",
+			mask:  fieldmask.New("*", "profile.*", "*.id", "attrs.*", "counts"),
+			input: reflect.TypeOf(User{}),
+		},
+		{
+			name:      "unknown paths",
+			mask:      fieldmask.New("name", "profile.agee", "nmae", "attrs.color.unknown"),
+			input:     reflect.TypeOf(User{}),
+			wantPaths: []string{"profile.agee", "nmae", "attrs.color.unknown"},
+			wantError: fieldmask.ErrUnknownField,
+		},
+		{
+			name:      "unresolvable wildcard",
+			mask:      fieldmask.New("profile.*.missing"),
+			input:     reflect.TypeOf(User{}),
+			wantPaths: []string{"profile.*.missing"},
+			wantError: fieldmask.ErrUnknownField,
+		},
+		{
+			name:      "path below a leaf",
+			mask:      fieldmask.New("name.first", "labels.env.value", "counts.1"),
+			input:     reflect.TypeOf(User{}),
+			wantPaths: []string{"name.first", "labels.env.value", "counts.1"},
+		},
+		{
+			name:      "non-struct type",
+			mask:      fieldmask.New("name"),
+			input:     reflect.TypeOf(""),
+			wantError: fieldmask.ErrNoStruct,
+		},
+		{
+			name:      "nil type",
+			mask:      fieldmask.New("name"),
+			input:     nil,
+			wantError: fieldmask.ErrNilInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mask.Validate(tt.input)
+
+			if tt.wantPaths == nil && tt.wantError == nil {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if tt.wantError != nil && !errors.Is(err, tt.wantError) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantError)
+			}
+
+			if tt.wantPaths != nil {
+				if !fieldmask.IsFieldProcessingError(err) {
+					t.Errorf("Validate() error = %v, want field processing error", err)
+				}
+				if got := fieldmask.FieldProcessingPaths(err); !reflect.DeepEqual(got, tt.wantPaths) {
+					t.Errorf("FieldProcessingPaths() = %v, want %v", got, tt.wantPaths)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFor(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	if err := fieldmask.ValidateFor[User](fieldmask.New("name")); err != nil {
+		t.Errorf("ValidateFor() unexpected error: %v", err)
+	}
+
+	err := fieldmask.ValidateFor[User](fieldmask.New("name.first"))
+	if !fieldmask.IsUnexpectedKindError(err) {
+		t.Errorf("ValidateFor() error = %v, want unexpected kind error", err)
+	}
+	if want := `failed to process field name.first at segment "first": unexpected field kind: string`; err.Error() != want {
+		t.Errorf("ValidateFor() error = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,163 @@
+package fieldmask
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// WireFormat selects how a FieldMask is encoded by its JSON and text marshalers.
+type WireFormat int
+
+const (
+	// WireObject encodes a FieldMask as a JSON object holding its paths, such as {"paths":["name","profile.age"]}.
+	// Its text form is the comma-separated list of paths, as produced by Parse. It is the default.
+	WireObject WireFormat = iota
+	// WireProto3 encodes a FieldMask following the proto3 JSON mapping of google.protobuf.FieldMask: a single
+	// comma-separated string with lowerCamelCase segments, such as "name,profile.birthDate" for the paths "name" and
+	// "profile.birth_date". The text form is the same string.
+	WireProto3
+)
+
+// wireObject is the JSON object form of a FieldMask.
+type wireObject struct {
+	Paths []string `json:"paths"`
+}
+
+// MarshalJSON encodes the FieldMask in the wire form selected by its Wire field.
+func (f FieldMask) MarshalJSON() ([]byte, error) {
+	if f.Wire != WireProto3 {
+		return json.Marshal(wireObject{Paths: f.Paths})
+	}
+
+	s, err := f.marshalProto3()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes either wire form: a JSON object holding the paths, or a proto3 string whose lowerCamelCase
+// segments are converted back to snake_case. The Wire field is set to the form found, so encoding the mask again
+// produces the same form. A JSON null leaves the FieldMask unchanged.
+func (f *FieldMask) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) == 0 || data[0] != '"' {
+		var obj wireObject
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		f.Paths, f.Wire = obj.Paths, WireObject
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if err := f.unmarshalProto3(s); err != nil {
+		return err
+	}
+	f.Wire = WireProto3
+	return nil
+}
+
+// MarshalText encodes the FieldMask as a comma-separated string. With WireProto3, segments are converted to
+// lowerCamelCase like in the proto3 JSON mapping; otherwise paths are written as they are.
+func (f FieldMask) MarshalText() ([]byte, error) {
+	if f.Wire != WireProto3 {
+		return []byte(strings.Join(f.Paths, pathListSeparator)), nil
+	}
+
+	s, err := f.marshalProto3()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText decodes a comma-separated string using the wire form already selected by the Wire field, so a
+// WireProto3 mask converts lowerCamelCase segments back to snake_case. See Parse for the accepted syntax.
+func (f *FieldMask) UnmarshalText(text []byte) error {
+	if f.Wire == WireProto3 {
+		return f.unmarshalProto3(string(text))
+	}
+
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	f.Paths = parsed.GetPaths()
+	return nil
+}
+
+// marshalProto3 returns the proto3 string form of the FieldMask. Paths that would not survive the conversion to
+// lowerCamelCase and back, such as "birthDate" or "birth__date", are rejected.
+func (f *FieldMask) marshalProto3() (string, error) {
+	camel := make([]string, len(f.Paths))
+	for i, path := range f.Paths {
+		camel[i] = lowerCamelCase(path)
+		if snakeCase(camel[i]) != path {
+			return "", &errFieldProcessing{fieldName: path, err: ErrIrreversiblePath}
+		}
+	}
+	return strings.Join(camel, pathListSeparator), nil
+}
+
+// unmarshalProto3 parses the proto3 string form s into the FieldMask, converting segments to snake_case. Paths that
+// would not survive the conversion back to lowerCamelCase, such as "birth_date", are rejected.
+func (f *FieldMask) unmarshalProto3(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	paths := parsed.GetPaths()
+	for i, path := range paths {
+		paths[i] = snakeCase(path)
+		if lowerCamelCase(paths[i]) != path {
+			return &errFieldProcessing{fieldName: path, err: ErrIrreversiblePath}
+		}
+	}
+	f.Paths = paths
+	return nil
+}
+
+// lowerCamelCase converts a snake_case path to lowerCamelCase by removing underscores and capitalizing the lowercase
+// ASCII letter following each of them, like the proto3 JSON name of a field.
+func lowerCamelCase(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	underscore := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '_' {
+			if underscore && c >= 'a' && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			b.WriteByte(c)
+		}
+		underscore = c == '_'
+	}
+	return b.String()
+}
+
+// snakeCase converts a lowerCamelCase path to snake_case by lowering every uppercase ASCII letter and prefixing it
+// with an underscore.
+func snakeCase(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' {
+			b.WriteByte('_')
+			c += 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,227 @@
+package fieldmask_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.g3deon.com/fieldmask"
+)
+
+func TestFieldMask_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		mask      *fieldmask.FieldMask
+		want      string
+		wantError error
+	}{
+		{
+			name: "object form by default",
+			mask: fieldmask.New("name", "profile.birth_date"),
+			want: `{"paths":["name","profile.birth_date"]}`,
+		},
+		{
+			name: "empty object form",
+			mask: &fieldmask.FieldMask{},
+			want: `{"paths":null}`,
+		},
+		{
+			name: "proto3 form",
+			mask: &fieldmask.FieldMask{Paths: []string{"name", "profile.birth_date"}, Wire: fieldmask.WireProto3},
+			want: `"name,profile.birthDate"`,
+		},
+		{
+			name: "empty proto3 form",
+			mask: &fieldmask.FieldMask{Wire: fieldmask.WireProto3},
+			want: `""`,
+		},
+		{
+			name:      "irreversible proto3 path",
+			mask:      &fieldmask.FieldMask{Paths: []string{"profile.birthDate"}, Wire: fieldmask.WireProto3},
+			wantError: fieldmask.ErrIrreversiblePath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.mask)
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Marshal() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldMask_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      []string
+		wantWire  fieldmask.WireFormat
+		wantError bool
+	}{
+		{
+			name:     "object form",
+			input:    `{"paths":["name","profile.birth_date"]}`,
+			want:     []string{"name", "profile.birth_date"},
+			wantWire: fieldmask.WireObject,
+		},
+		{
+			name:     "proto3 form",
+			input:    `"name, profile.birthDate"`,
+			want:     []string{"name", "profile.birth_date"},
+			wantWire: fieldmask.WireProto3,
+		},
+		{
+			name:     "empty proto3 form",
+			input:    `""`,
+			wantWire: fieldmask.WireProto3,
+		},
+		{
+			name:      "underscore in proto3 form",
+			input:     `"profile.birth_date"`,
+			wantError: true,
+		},
+		{
+			name:      "syntax error in proto3 form",
+			input:     `"name,,age"`,
+			wantError: true,
+		},
+		{
+			name:      "invalid type",
+			input:     `["name"]`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got fieldmask.FieldMask
+			err := json.Unmarshal([]byte(tt.input), &got)
+
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("Unmarshal() expected error, got paths %v", got.Paths)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unmarshal() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.GetPaths(), tt.want) {
+				t.Errorf("Unmarshal() paths = %v, want %v", got.GetPaths(), tt.want)
+			}
+			if got.Wire != tt.wantWire {
+				t.Errorf("Unmarshal() wire = %v, want %v", got.Wire, tt.wantWire)
+			}
+		})
+	}
+}
+
+func TestFieldMask_JSON_Embedded(t *testing.T) {
+	type UpdateRequest struct {
+		Name       string               `json:"name"`
+		UpdateMask *fieldmask.FieldMask `json:"updateMask"`
+	}
+
+	var req UpdateRequest
+	if err := json.Unmarshal([]byte(`{"name":"John","updateMask":"name,displayName"}`), &req); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	if want := []string{"name", "display_name"}; !reflect.DeepEqual(req.UpdateMask.GetPaths(), want) {
+		t.Errorf("Unmarshal() paths = %v, want %v", req.UpdateMask.GetPaths(), want)
+	}
+
+	got, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+	if want := `{"name":"John","updateMask":"name,displayName"}`; string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestFieldMask_JSON_ByValue(t *testing.T) {
+	type UpdateRequest struct {
+		Name       string              `json:"name"`
+		UpdateMask fieldmask.FieldMask `json:"updateMask"`
+	}
+
+	req := UpdateRequest{
+		Name:       "John",
+		UpdateMask: fieldmask.FieldMask{Paths: []string{"birth_date"}, Wire: fieldmask.WireProto3},
+	}
+
+	for _, v := range []any{req, &req} {
+		got, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal(%T) unexpected error: %v", v, err)
+		}
+		if want := `{"name":"John","updateMask":"birthDate"}`; string(got) != want {
+			t.Errorf("Marshal(%T) = %s, want %s", v, got, want)
+		}
+	}
+
+	var decoded UpdateRequest
+	if err := json.Unmarshal([]byte(`{"name":"John","updateMask":"birthDate"}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, req) {
+		t.Errorf("Unmarshal() = %+v, want %+v", decoded, req)
+	}
+}
+
+func TestFieldMask_MarshalText(t *testing.T) {
+	tests := []struct {
+		name string
+		mask *fieldmask.FieldMask
+		want string
+	}{
+		{
+			name: "object form keeps paths",
+			mask: fieldmask.New("name", "profile.birth_date"),
+			want: "name,profile.birth_date",
+		},
+		{
+			name: "proto3 form converts segments",
+			mask: &fieldmask.FieldMask{Paths: []string{"name", "profile.birth_date"}, Wire: fieldmask.WireProto3},
+			want: "name,profile.birthDate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mask.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalText() = %s, want %s", got, tt.want)
+			}
+
+			decoded := &fieldmask.FieldMask{Wire: tt.mask.Wire}
+			if err := decoded.UnmarshalText(got); err != nil {
+				t.Fatalf("UnmarshalText() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(decoded.GetPaths(), tt.mask.GetPaths()) {
+				t.Errorf("UnmarshalText() paths = %v, want %v", decoded.GetPaths(), tt.mask.GetPaths())
+			}
+		})
+	}
+}